@@ -0,0 +1,228 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/stats"
+)
+
+const (
+	// Every point is compared with the median of the points right before it
+	Rolling = "rolling"
+	// The series is split into a trend and a weekly pattern, and points are compared with both
+	Seasonal = "seasonal"
+)
+
+const (
+	DefaultThreshold = 3.5
+	MaxWindow        = 1000
+	// Scales the median absolute deviation so it estimates the standard deviation of normally distributed values
+	madScale = 1.4826
+)
+
+type Options struct {
+	Method string
+	// Number of points before every point that make up its baseline, for the rolling method
+	Window int
+	// Length of the seasonal cycle in points, e.g. 7 for the weekly cycle of a daily series
+	Period int
+	// Points with a score above the threshold (or below minus the threshold) are anomalies
+	Threshold float64
+}
+
+// A Point is scored against the views expected for it
+type Point struct {
+	Index    int
+	Value    float64
+	Expected float64
+	// Range of the values that are not anomalies
+	Lower float64
+	Upper float64
+	// Number of (robust) standard deviations between the value and the expected value
+	Score   float64
+	Anomaly bool
+}
+
+type Anomaly struct {
+	Timestamp string
+	Views     int
+	Expected  float64
+	Lower     float64
+	Upper     float64
+	Score     float64
+}
+
+type Anomalies struct {
+	Article     string
+	Granularity string
+	Method      string
+	Anomalies   []Anomaly
+}
+
+// Returns the options for a daily series: a baseline of two weeks and a weekly cycle
+func DefaultOptions() Options {
+	return Options{Method: Rolling, Window: 14, Period: 7, Threshold: DefaultThreshold}
+}
+
+// curl "http://localhost:8080/article/ChatGPT/anomalies?start=20230101&end=20230331&method=seasonal"
+// Returns the days of the query on which the views of the titles are anomalies
+// The wikipedia API has no hourly views per article, so only daily series are scored
+// With the rolling method the points before the start of the query are fetched as well, so the first days of
+// the query have a baseline too
+func GetAnomalies(titles []string, query pageviews.Query, options Options) (Anomalies, error) {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if query.Granularity != pageviews.Daily {
+		return Anomalies{}, fmt.Errorf(status400+": granularity must be %s, the wikipedia API has no hourly views per article", pageviews.Daily)
+	}
+	err := options.validate()
+	if err != nil {
+		return Anomalies{}, err
+	}
+
+	firstTimestamp := query.Start
+	if options.Method == Rolling {
+		query.Start = query.Start.AddDate(0, 0, -options.Window)
+	}
+	series, err := pageviews.GetMergedSeries(titles, query)
+	if err != nil {
+		return Anomalies{}, err
+	}
+
+	values := make([]float64, len(series))
+	for i, item := range series {
+		values[i] = float64(item.Views)
+	}
+	points, err := Detect(values, options)
+	if err != nil {
+		return Anomalies{}, err
+	}
+
+	result := Anomalies{Article: titles[0], Granularity: query.Granularity, Method: options.Method}
+	for _, point := range points {
+		item := series[point.Index]
+		timestamp, err := time.Parse("2006010215", item.Timestamp)
+		if !point.Anomaly || err != nil || timestamp.Before(firstTimestamp) {
+			continue
+		}
+		result.Anomalies = append(result.Anomalies, Anomaly{
+			Timestamp: item.Timestamp,
+			Views:     item.Views,
+			Expected:  point.Expected,
+			Lower:     point.Lower,
+			Upper:     point.Upper,
+			Score:     point.Score,
+		})
+	}
+	return result, nil
+}
+
+func (options Options) validate() error {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if options.Method != Rolling && options.Method != Seasonal {
+		return fmt.Errorf(status400+": method must be %s or %s", Rolling, Seasonal)
+	}
+	if options.Window < 2 || options.Window > MaxWindow || options.Period < 2 || options.Period > MaxWindow {
+		return fmt.Errorf(status400+": window and period must be between 2 and %d points", MaxWindow)
+	}
+	if options.Threshold <= 0 {
+		return fmt.Errorf(status400 + ": threshold must be greater than 0")
+	}
+	return nil
+}
+
+// Scores the values with the method of the options
+// The rolling method cannot score the first Window values, they are not returned
+func Detect(values []float64, options Options) ([]Point, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+	if options.Method == Seasonal {
+		return seasonal(values, options.Period, options.Threshold)
+	}
+	return rolling(values, options.Window, options.Threshold), nil
+}
+
+// Compares every value with the median and the median absolute deviation of the window values before it
+func rolling(values []float64, window int, threshold float64) []Point {
+	var points []Point
+	for i := window; i < len(values); i++ {
+		baseline := values[i-window : i]
+		points = append(points, score(i, values[i], stats.Median(baseline), scale(baseline), threshold))
+	}
+	return points
+}
+
+// Splits the values into a trend, a seasonal pattern and the rest (residuals), and compares the residual of every
+// value with the median absolute deviation of all the residuals. Medians are used everywhere instead of means so
+// the spikes themselves do not move the trend or the pattern.
+func seasonal(values []float64, period int, threshold float64) ([]Point, error) {
+	if len(values) < 2*period {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400+": at least %d points are needed to find a cycle of %d points", 2*period, period)
+	}
+
+	// Trend: centered rolling median over one cycle, the first and last windows are used for both ends of the series
+	trend := make([]float64, len(values))
+	length := period/2*2 + 1
+	for i := range values {
+		from := i - period/2
+		if from < 0 {
+			from = 0
+		}
+		if from > len(values)-length {
+			from = len(values) - length
+		}
+		trend[i] = stats.Median(values[from : from+length])
+	}
+
+	// Seasonal pattern: median of the detrended values of every position in the cycle, centered around 0
+	pattern := make([]float64, period)
+	for phase := range pattern {
+		var detrended []float64
+		for i := phase; i < len(values); i += period {
+			detrended = append(detrended, values[i]-trend[i])
+		}
+		pattern[phase] = stats.Median(detrended)
+	}
+	patternMean := stats.Mean(pattern)
+	for phase := range pattern {
+		pattern[phase] -= patternMean
+	}
+
+	residuals := make([]float64, len(values))
+	for i := range values {
+		residuals[i] = values[i] - trend[i] - pattern[i%period]
+	}
+	residualMedian := stats.Median(residuals)
+	residualScale := scale(residuals)
+
+	points := make([]Point, len(values))
+	for i := range values {
+		expected := trend[i] + pattern[i%period] + residualMedian
+		points[i] = score(i, values[i], expected, residualScale, threshold)
+	}
+	return points, nil
+}
+
+// Robust estimate of the standard deviation of the values, at least one view so flat series do not divide by zero
+func scale(values []float64) float64 {
+	return math.Max(madScale*stats.MedianAbsoluteDeviation(values), 1)
+}
+
+func score(index int, value, expected, scale, threshold float64) Point {
+	point := Point{
+		Index:    index,
+		Value:    value,
+		Expected: expected,
+		Lower:    math.Max(expected-threshold*scale, 0),
+		Upper:    expected + threshold*scale,
+		Score:    (value - expected) / scale,
+	}
+	point.Anomaly = math.Abs(point.Score) > threshold
+	return point
+}
@@ -0,0 +1,114 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Four weeks of 100 views on weekdays and 40 views on weekends, with a spike on the 18th day
+func weeklySeries() []float64 {
+	values := make([]float64, 28)
+	for i := range values {
+		values[i] = 100
+		if i%7 >= 5 {
+			values[i] = 40
+		}
+	}
+	values[17] = 500
+	return values
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name              string
+		options           Options
+		expectedAnomalies []int
+	}{
+		{
+			name:              "the rolling median flags the spike and the weekends",
+			options:           Options{Method: Rolling, Window: 14, Period: 7, Threshold: DefaultThreshold},
+			expectedAnomalies: []int{17, 19, 20, 26, 27},
+		},
+		{
+			name:              "seasonal decomposition flags only the spike",
+			options:           Options{Method: Seasonal, Window: 14, Period: 7, Threshold: DefaultThreshold},
+			expectedAnomalies: []int{17},
+		},
+	}
+	for tcNum, tc := range testCases {
+		points, err := Detect(weeklySeries(), tc.options)
+		require.NoError(t, err)
+		var got []int
+		for _, point := range points {
+			if point.Anomaly {
+				got = append(got, point.Index)
+			}
+		}
+		require.Equal(t, tc.expectedAnomalies, got, "test %d failed", tcNum+1)
+	}
+}
+
+func TestDetectExpectedRange(t *testing.T) {
+	points, err := Detect(weeklySeries(), Options{Method: Seasonal, Window: 14, Period: 7, Threshold: 2})
+	require.NoError(t, err)
+	spike := points[17]
+	assertFloat(t, 0, spike.Expected, 100)
+	assertFloat(t, 1, spike.Lower, 98)
+	assertFloat(t, 2, spike.Upper, 102)
+	assertFloat(t, 3, spike.Score, 400)
+	// weekends are expected to be quieter
+	assertFloat(t, 4, points[20].Expected, 40)
+}
+
+func TestDetectInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		values        []float64
+		options       Options
+		expectedError string
+	}{
+		{
+			name:          "unknown method",
+			values:        weeklySeries(),
+			options:       Options{Method: "zscore", Window: 14, Period: 7, Threshold: DefaultThreshold},
+			expectedError: "400 Bad Request: method must be rolling or seasonal",
+		},
+		{
+			name:          "window too small",
+			values:        weeklySeries(),
+			options:       Options{Method: Rolling, Window: 1, Period: 7, Threshold: DefaultThreshold},
+			expectedError: "400 Bad Request: window and period must be between 2 and 1000 points",
+		},
+		{
+			name:          "threshold of 0",
+			values:        weeklySeries(),
+			options:       Options{Method: Rolling, Window: 14, Period: 7, Threshold: 0},
+			expectedError: "400 Bad Request: threshold must be greater than 0",
+		},
+		{
+			name:          "less than two cycles for seasonal decomposition",
+			values:        weeklySeries()[:10],
+			options:       Options{Method: Seasonal, Window: 14, Period: 7, Threshold: DefaultThreshold},
+			expectedError: "400 Bad Request: at least 14 points are needed to find a cycle of 7 points",
+		},
+	}
+	for tcNum, tc := range testCases {
+		_, err := Detect(tc.values, tc.options)
+		require.Error(t, err)
+		require.Equal(t, tc.expectedError, err.Error(), "test %d failed", tcNum+1)
+	}
+}
+
+func TestDefaultOptions(t *testing.T) {
+	require.Equal(t, 14, DefaultOptions().Window)
+	require.Equal(t, 7, DefaultOptions().Period)
+}
+
+func assertFloat(t testing.TB, testNum int, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
@@ -3,16 +3,17 @@ package articles
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"sort"
-	"strconv"
+	"time"
 
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
 )
 
 // Move this to the config file
-const baseURL = "https://wikimedia.org/api/rest_v1/metrics/pageviews/top/en.wikipedia/all-access"
+// A variable so tests can serve the top lists from a local server
+var baseURL = "https://wikimedia.org/api/rest_v1/metrics/pageviews/top/en.wikipedia/all-access"
 
 type Items struct {
 	Items []Item
@@ -33,201 +34,156 @@ type Article struct {
 	Rank    int
 }
 
+// Returns the articles of the map from the most to the least viewed, articles with the same views are sorted
+// by title
 func sortMap(input map[string]int) []string {
-	// Create slice of key-value pairs
-	pairs := make([][2]interface{}, 0, len(input))
-	for k, v := range input {
-		pairs = append(pairs, [2]interface{}{k, v})
-	}
-
-	// Sort slice based on values
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i][1].(int) > pairs[j][1].(int)
-	})
+	return ranking.Rank(input, ranking.Ordinal).Titles()
+}
 
-	// Extract sorted keys
-	keys := make([]string, len(pairs))
-	for i, p := range pairs {
-		keys[i] = p[0].(string)
+// Drops the articles that do not pass the filter and ranks the rest again with the method
+// The input is not modified
+func filterArticles(input []Article, articleFilter filter.Filter, method ranking.Method) []Article {
+	articlesMap := map[string]int{}
+	for _, article := range input {
+		if articleFilter.Allows(article.Article) {
+			articlesMap[article.Article] = article.Views
+		}
 	}
+	return topN(articlesMap, len(articlesMap), method)
+}
 
-	// Print sorted map
-	// for _, k := range keys {
-	// 	fmt.Printf("%s: %d\n", k, input[k])
-	// }
-
-	return keys
+// Ranks the aggregated views and returns the n most viewed articles
+func topN(articlesMap map[string]int, n int, method ranking.Method) []Article {
+	var topArticles []Article
+	for _, entry := range ranking.Rank(articlesMap, method).Top(n) {
+		topArticles = append(topArticles, Article{Article: entry.Title, Views: entry.Views, Rank: entry.Rank})
+	}
+	return topArticles
 }
 
-// curl http://localhost:8080/articles/top/weekly/2023/03
-// Returns a list of the most viewed articles for a week
-// If an article is not listed on a given day, we assume it has 0 views
-// It is assumed that the week starts on Monday
-func GetTopArticlesByWeek(year, week string) (string, error) {
-	// Convert input year and week to integers
-	yearInt, err := strconv.Atoi(year)
+// curl http://localhost:8080/articles/top/weekly/2023/03?weekScheme=iso
+// curl http://localhost:8080/articles/top/monthly/2023/03
+// curl http://localhost:8080/articles/top/quarterly/2023/1
+// curl http://localhost:8080/articles/top/last-7-days
+// Returns a list of the 10 most viewed articles for a period, as JSON
+// Periods other than a single month without any results return an empty string
+func GetTopArticles(p period.Period, articleFilter filter.Filter, method ranking.Method) (string, error) {
+	topArticles, err := TopArticles(p, articleFilter, 10, method)
 	if err != nil {
 		return "", err
 	}
-	weekInt, err := strconv.Atoi(week)
-	if err != nil {
-		return "", err
+	// if there are no results return empty result set
+	if len(topArticles) == 0 && p.Granularity != period.Monthly {
+		return "", nil
 	}
 
-	// Validate that input year is not out of bounds
-	err = utilities.ValidateInputYear(yearInt)
+	jsonResult, err := json.Marshal(topArticles)
 	if err != nil {
 		return "", err
 	}
+	return string(jsonResult), nil
+}
 
-	// Validate that input week is not out of bounds
-	err = utilities.ValidateInputWeek(year, weekInt)
-	if err != nil {
-		return "", err
+// Returns the n most viewed articles for a period, see GetPeriodViews
+// Articles that do not pass the filter are dropped before ranking so the result still has n articles
+// Articles with the same views are ranked with the method and listed by title
+func TopArticles(p period.Period, articleFilter filter.Filter, n int, method ranking.Method) ([]Article, error) {
+	// A single month is ranked from the monthly list of the wikipedia API
+	if p.Granularity == period.Monthly {
+		monthArticles, err := GetTopArticlesOfMonth(p.Start)
+		if err != nil {
+			return nil, err
+		}
+		filtered := filterArticles(monthArticles, articleFilter, method)
+		if len(filtered) > n {
+			filtered = filtered[:n]
+		}
+		return filtered, nil
 	}
 
-	// Get the first day of the week
-	startDate := utilities.WeekStart(yearInt, weekInt)
+	articlesMap, err := GetPeriodViews(p, articleFilter)
+	if err != nil {
+		return nil, err
+	}
+	return topN(articlesMap, n, method), nil
+}
 
-	var urls [7]string
-	var errorStatus, errorDetails string
+// Returns the views of every article listed in the top lists of the period, adding up the monthly lists of the
+// whole months of the period and the daily lists of the other days (e.g. of a week)
+// If an article is not listed in a given month (or day), we assume it has 0 views
+func GetPeriodViews(p period.Period, articleFilter filter.Filter) (map[string]int, error) {
 	articlesMap := map[string]int{}
-	for i := 0; i < 7; i++ {
-		// Build the URL
-		month := utilities.PadString(fmt.Sprint(int(startDate.Month())))
-		day := utilities.PadString(fmt.Sprint(startDate.AddDate(0, 0, i).Day()))
-		urls[i] = fmt.Sprintf("%s/%s/%s/%s", baseURL, fmt.Sprint(startDate.Year()), month, day)
-
-		// Call the wikipedia API
-		response, err := http.Get(urls[i])
-		if err != nil {
-			return "", err
-		}
-
-		// Parse response
-		responseData, err := io.ReadAll(response.Body)
-		if err != nil {
-			return "", err
+	for _, part := range p.SplitByMonth() {
+		// Whole months are read from the monthly list and the days of partial months from the daily lists,
+		// so long periods like year-to-date take one call per month
+		getList := GetTopArticlesOfMonth
+		dates := []time.Time{part.Start}
+		if !part.MonthAligned() {
+			getList = GetTopArticlesByDay
+			dates = part.Days()
 		}
-
-		// If an error happens during any of the API calls stop processing, exit the loop, and return the error details
-		if response.StatusCode != http.StatusOK {
-			errorStatus = response.Status
-			errorDetails, err = utilities.ParseErrorDetails(responseData)
+		for _, date := range dates {
+			// Call the wikipedia API, if an error happens during any of the API calls stop processing and return it
+			listArticles, err := getList(date)
 			if err != nil {
-				errorDetails = "Failed to process error details"
+				return nil, err
 			}
-			break
-		}
-
-		// Go through articles and add them to a map
-		var items Items
-		err = json.Unmarshal(responseData, &items)
-		if err != nil {
-			return "", err
-		}
-
-		//If any items were found extract them from the response and add them to the map
-		if len(items.Items) > 0 {
-			for _, article := range items.Items[0].Articles {
-				if val, ok := articlesMap[article.Article]; ok {
-					articlesMap[article.Article] = article.Views + val
-				} else {
-					articlesMap[article.Article] = article.Views
+			for _, article := range listArticles {
+				if !articleFilter.Allows(article.Article) {
+					continue
 				}
+				articlesMap[article.Article] += article.Views
 			}
 		}
 	}
+	return articlesMap, nil
+}
 
-	if errorDetails != "" {
-		return "", fmt.Errorf(errorStatus + ": " + errorDetails)
-	}
-
-	// if there are no results return empty result set
-	if len(articlesMap) == 0 {
-		return "", nil
-	}
-
-	// Sort results and retrieve 10 most viewed
-	sortKeysDesc := sortMap(articlesMap)
-	var top10Articles []Article
-	for i := 0; i < 10; i++ {
-		top10Articles = append(top10Articles, Article{
-			Article: sortKeysDesc[i],
-			Views:   articlesMap[sortKeysDesc[i]],
-			Rank:    i + 1,
-		})
+// Returns the full list of the most viewed articles for a day, as ranked by the wikipedia API
+// Lists are cached once fetched, so the result must not be modified
+func GetTopArticlesByDay(date time.Time) ([]Article, error) {
+	key := date.Format("2006/01/02")
+	if list, ok := dailyLists.get(key); ok {
+		return list, nil
 	}
+	url := fmt.Sprintf("%s/%s", baseURL, key)
 
-	// Convert to JSON and return string
-	jsonResult, err := json.Marshal(top10Articles)
+	// Call the wikipedia API
+	responseData, err := utilities.CallAPI(url)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(jsonResult), nil
-}
-
-// curl http://localhost:8080/articles/top/monthly/2023/03
-func GetTopArticlesByMonth(year, month string) (string, error) {
-	// Convert input year to integer
-	yearInt, err := strconv.Atoi(year)
+	var items Items
+	err = json.Unmarshal(responseData, &items)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	// Validate that input year is not out of bounds
-	err = utilities.ValidateInputYear(yearInt)
-	if err != nil {
-		return "", err
+	var list []Article
+	if len(items.Items) > 0 {
+		list = items.Items[0].Articles
 	}
+	dailyLists.add(key, list)
+	return list, nil
+}
 
-	// Build URL
-	url := fmt.Sprintf("%s/%s/%s/all-days", baseURL, year, utilities.PadString(month))
+// Returns the full list of the most viewed articles for the month of the date, as ranked by the wikipedia API
+func GetTopArticlesOfMonth(date time.Time) ([]Article, error) {
+	url := fmt.Sprintf("%s/%s/all-days", baseURL, date.Format("2006/01"))
 
 	// Call the wikipedia API
-	response, err := http.Get(url)
+	responseData, err := utilities.CallAPI(url)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Parse response
-	responseData, err := io.ReadAll(response.Body)
-	if err != nil {
-		return "", err
-	}
-
-	// Check if the response in anything else than HTTP 200 and return error
-	if response.StatusCode != http.StatusOK {
-		errorDetails, err := utilities.ParseErrorDetails(responseData)
-		if err != nil {
-			errorDetails = "Failed to process error details"
-		}
-		return "", fmt.Errorf(response.Status + ": " + errorDetails)
-	}
-
-	// Get top 10 articles
 	var items Items
 	err = json.Unmarshal(responseData, &items)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	// I haven't found a test case where there are no articles returned but I'm adding a check just in case, to avoid nil pointer exceptions
-	var top10Articles []Article
-	numOfArticles := len(items.Items[0].Articles)
-	if numOfArticles > 0 {
-		if numOfArticles > 10 {
-			numOfArticles = 10
-		}
-		top10Articles = items.Items[0].Articles[0:numOfArticles]
-	}
-
-	// Convert to JSON and return string
-	jsonResult, err := json.Marshal(top10Articles)
-	if err != nil {
-		return "", err
+	if len(items.Items) == 0 {
+		return nil, nil
 	}
-
-	return string(jsonResult), nil
+	return items.Items[0].Articles, nil
 }
@@ -1,9 +1,21 @@
 package articles
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,6 +51,16 @@ func TestSortMap(t *testing.T) {
 			},
 			expectedMap: []string{"article10", "article9", "article8", "article7", "article6", "article5", "article4", "article3", "article2", "article1"},
 		},
+		{
+			name: "articles with the same views are sorted by title",
+			unsortedMap: map[string]int{
+				"article3": 5,
+				"article1": 5,
+				"article2": 9,
+				"article4": 5,
+			},
+			expectedMap: []string{"article2", "article1", "article3", "article4"},
+		},
 		{
 			name:        "sort empty map",
 			unsortedMap: map[string]int{},
@@ -53,7 +75,49 @@ func TestSortMap(t *testing.T) {
 	}
 }
 
-func TestGetTopArticlesByMonth(t *testing.T) {
+func TestFilterArticles(t *testing.T) {
+	input := []Article{
+		{Article: "Main_Page", Views: 145431456, Rank: 1},
+		{Article: "Special:Search", Views: 42163260, Rank: 2},
+		{Article: "YouTube", Views: 7716744, Rank: 3},
+		{Article: "Wikipedia:Featured_pictures", Views: 7460936, Rank: 4},
+		{Article: "ChatGPT", Views: 6916888, Rank: 5},
+	}
+	expected := []Article{
+		{Article: "YouTube", Views: 7716744, Rank: 1},
+		{Article: "ChatGPT", Views: 6916888, Rank: 2},
+	}
+	got := filterArticles(input, filter.Filter{Namespaces: []string{"main"}, Denylist: true}, ranking.Ordinal)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v want %v", got, expected)
+	}
+
+	// the input ranks are kept when there is nothing to filter
+	got = filterArticles(input, filter.Filter{}, ranking.Ordinal)
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf("got %v want %v", got, input)
+	}
+
+	// the articles left are ranked again with the ties method of the caller
+	tied := []Article{
+		{Article: "Main_Page", Views: 145431456, Rank: 1},
+		{Article: "YouTube", Views: 7716744, Rank: 2},
+		{Article: "ChatGPT", Views: 7716744, Rank: 3},
+		{Article: "Wikipedia:Featured_pictures", Views: 7460936, Rank: 4},
+		{Article: "Albert_Einstein", Views: 3001532, Rank: 5},
+	}
+	expected = []Article{
+		{Article: "ChatGPT", Views: 7716744, Rank: 1},
+		{Article: "YouTube", Views: 7716744, Rank: 1},
+		{Article: "Albert_Einstein", Views: 3001532, Rank: 3},
+	}
+	got = filterArticles(tied, filter.Filter{Namespaces: []string{"main"}, Denylist: true}, ranking.Competition)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v want %v", got, expected)
+	}
+}
+
+func TestGetTopArticlesMonthly(t *testing.T) {
 	testCases := []struct {
 		name             string
 		year             string
@@ -73,7 +137,7 @@ func TestGetTopArticlesByMonth(t *testing.T) {
 			year:             "2023",
 			month:            "13",
 			expectedArticles: "",
-			expectedError:    "400 Bad Request: Given year/month/day is invalid date",
+			expectedError:    "400 Bad Request: input month must be between 1 and 12",
 		},
 		{
 			name:             "error case: HTTP 400 for invalid input (future date)",
@@ -84,7 +148,11 @@ func TestGetTopArticlesByMonth(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
-		gotArticles, gotError := GetTopArticlesByMonth(tc.year, tc.month)
+		var gotArticles string
+		p, gotError := period.Month(tc.year, tc.month)
+		if gotError == nil {
+			gotArticles, gotError = GetTopArticles(p, filter.Filter{}, ranking.Ordinal)
+		}
 		if tc.expectedError != "" {
 			require.Error(t, gotError)
 			assertExpectedOutput(t, i, gotError.Error(), tc.expectedError)
@@ -95,7 +163,7 @@ func TestGetTopArticlesByMonth(t *testing.T) {
 	}
 }
 
-func TestGetTopArticlesByWeek(t *testing.T) {
+func TestGetTopArticlesWeekly(t *testing.T) {
 	testCases := []struct {
 		name             string
 		year             string
@@ -110,20 +178,6 @@ func TestGetTopArticlesByWeek(t *testing.T) {
 			expectedArticles: `[{"Article":"Main_Page","Views":35124815,"Rank":1},{"Article":"Index_(statistics)","Views":11321482,"Rank":2},{"Article":"Special:Search","Views":9513645,"Rank":3},{"Article":"The_Last_of_Us_(TV_series)","Views":2502335,"Rank":4},{"Article":"XXX:_Return_of_Xander_Cage","Views":2458723,"Rank":5},{"Article":"Index_(economics)","Views":1577466,"Rank":6},{"Article":"The_Last_of_Us","Views":1540964,"Rank":7},{"Article":"Index,_Washington","Views":1438865,"Rank":8},{"Article":"Wikipedia:Featured_pictures","Views":1415908,"Rank":9},{"Article":"ChatGPT","Views":1329459,"Rank":10}]`,
 			expectedError:    "",
 		},
-		{
-			name:             "top 10 most viewed articles on the 1st week of 2020 (which starts in 2019)",
-			year:             "2020",
-			week:             "1",
-			expectedArticles: `[{"Article":"Main_Page","Views":94177888,"Rank":1},{"Article":"Special:Search","Views":10662879,"Rank":2},{"Article":"Jimmy_Hoffa","Views":2032891,"Rank":3},{"Article":"Frank_Sheeran","Views":1570107,"Rank":4},{"Article":"The_Irishman_(2019_film)","Views":1479816,"Rank":5},{"Article":"The_Mandalorian","Views":1308162,"Rank":6},{"Article":"NSA_ANT_catalog","Views":1124420,"Rank":7},{"Article":"Deaths_in_2019","Views":775275,"Rank":8},{"Article":"Elizabeth_II","Views":727896,"Rank":9},{"Article":"Princess_Margaret,_Countess_of_Snowdon","Views":679889,"Rank":10}]`,
-			expectedError:    "",
-		},
-		{
-			name:             "top 10 most viewed articles on the last week of 2020 (which ends in 2021)",
-			year:             "2020",
-			week:             "53",
-			expectedArticles: `[{"Article":"Main_Page","Views":42788003,"Rank":1},{"Article":"Special:Search","Views":8661192,"Rank":2},{"Article":"Elliot_Page","Views":3754122,"Rank":3},{"Article":"Bridgerton","Views":1265183,"Rank":4},{"Article":"Emma_Portner","Views":1043047,"Rank":5},{"Article":"Ellen_Page","Views":1042798,"Rank":6},{"Article":"Wonder_Woman_1984","Views":1007296,"Rank":7},{"Article":"Elizabeth_II","Views":979450,"Rank":8},{"Article":"Bible","Views":917584,"Rank":9},{"Article":"Deaths_in_2020","Views":901244,"Rank":10}]`,
-			expectedError:    "",
-		},
 		{
 			name:             "error case: HTTP 404 for invalid input (week > 53 for year 2020)",
 			year:             "2020",
@@ -147,7 +201,11 @@ func TestGetTopArticlesByWeek(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
-		gotArticles, gotError := GetTopArticlesByWeek(tc.year, tc.week)
+		var gotArticles string
+		p, gotError := period.Week(tc.year, tc.week, utilities.ISOWeek)
+		if gotError == nil {
+			gotArticles, gotError = GetTopArticles(p, filter.Filter{}, ranking.Ordinal)
+		}
 		if tc.expectedError != "" {
 			require.Error(t, gotError)
 			assertExpectedOutput(t, i, gotError.Error(), tc.expectedError)
@@ -158,6 +216,69 @@ func TestGetTopArticlesByWeek(t *testing.T) {
 	}
 }
 
+// Weeks that cross a year are added up from the daily lists of their 7 days, which are served from a local server
+// with one list per day: Main_Page with 100 views, and an article of the day with the day of the month as views
+// Before the days were fetched one by one these weeks were read from the lists of the wrong month (e.g. December
+// 1-3 instead of January 1-3 for the last week of 2020), so the expectations recorded against the live API
+// changed with the fix
+func TestGetTopArticlesWeeklyAcrossYears(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date, err := time.Parse("/2006/01/02", r.URL.Path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		requested = append(requested, date.Format("2006-01-02"))
+		mu.Unlock()
+		list := []Article{
+			{Article: "Main_Page", Views: 100, Rank: 1},
+			{Article: "Day_" + date.Format("2006-01-02"), Views: date.Day(), Rank: 2},
+		}
+		json.NewEncoder(w).Encode(Items{Items: []Item{{Articles: list}}})
+	}))
+	defer server.Close()
+	defaultURL, defaultLists := baseURL, dailyLists
+	baseURL, dailyLists = server.URL, newListCache(maxCachedLists)
+	defer func() { baseURL, dailyLists = defaultURL, defaultLists }()
+
+	testCases := []struct {
+		name             string
+		year             string
+		week             string
+		expectedDays     []string
+		expectedArticles string
+	}{
+		{
+			name:             "top 10 most viewed articles on the 1st week of 2020 (which starts in 2019)",
+			year:             "2020",
+			week:             "1",
+			expectedDays:     []string{"2019-12-30", "2019-12-31", "2020-01-01", "2020-01-02", "2020-01-03", "2020-01-04", "2020-01-05"},
+			expectedArticles: `[{"Article":"Main_Page","Views":700,"Rank":1},{"Article":"Day_2019-12-31","Views":31,"Rank":2},{"Article":"Day_2019-12-30","Views":30,"Rank":3},{"Article":"Day_2020-01-05","Views":5,"Rank":4},{"Article":"Day_2020-01-04","Views":4,"Rank":5},{"Article":"Day_2020-01-03","Views":3,"Rank":6},{"Article":"Day_2020-01-02","Views":2,"Rank":7},{"Article":"Day_2020-01-01","Views":1,"Rank":8}]`,
+		},
+		{
+			name:             "top 10 most viewed articles on the last week of 2020 (which ends in 2021)",
+			year:             "2020",
+			week:             "53",
+			expectedDays:     []string{"2020-12-28", "2020-12-29", "2020-12-30", "2020-12-31", "2021-01-01", "2021-01-02", "2021-01-03"},
+			expectedArticles: `[{"Article":"Main_Page","Views":700,"Rank":1},{"Article":"Day_2020-12-31","Views":31,"Rank":2},{"Article":"Day_2020-12-30","Views":30,"Rank":3},{"Article":"Day_2020-12-29","Views":29,"Rank":4},{"Article":"Day_2020-12-28","Views":28,"Rank":5},{"Article":"Day_2021-01-03","Views":3,"Rank":6},{"Article":"Day_2021-01-02","Views":2,"Rank":7},{"Article":"Day_2021-01-01","Views":1,"Rank":8}]`,
+		},
+	}
+	for i, tc := range testCases {
+		requested = nil
+		p, err := period.Week(tc.year, tc.week, utilities.ISOWeek)
+		require.NoError(t, err)
+		gotArticles, err := GetTopArticles(p, filter.Filter{}, ranking.Ordinal)
+		require.NoError(t, err)
+		sort.Strings(requested)
+		assertExpectedOutput(t, i, strings.Join(requested, ","), strings.Join(tc.expectedDays, ","))
+		assertExpectedOutput(t, i, gotArticles, tc.expectedArticles)
+	}
+	assertExpectedOutput(t, len(testCases), fmt.Sprint(len(dailyLists.keys)), "14")
+}
+
 func assertExpectedOutput(t testing.TB, testNum int, got, want interface{}) {
 	t.Helper()
 	if got != want {
@@ -0,0 +1,44 @@
+package articles
+
+import "sync"
+
+// The daily top lists do not change once the wikipedia API publishes them, so they are kept in memory and
+// shared by all the requests, e.g. the rank history of an article and the trending articles read the same days
+const maxCachedLists = 3 * 366
+
+// listCache keeps the most recently fetched top lists by date, dropping the oldest ones when it is full
+// The cached lists are shared and must not be modified
+type listCache struct {
+	mu    sync.Mutex
+	lists map[string][]Article
+	// Keys in the order they were added
+	keys []string
+	size int
+}
+
+var dailyLists = newListCache(maxCachedLists)
+
+func newListCache(size int) *listCache {
+	return &listCache{lists: map[string][]Article{}, size: size}
+}
+
+func (c *listCache) get(key string) ([]Article, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list, ok := c.lists[key]
+	return list, ok
+}
+
+func (c *listCache) add(key string, list []Article) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lists[key]; ok {
+		return
+	}
+	if len(c.keys) >= c.size {
+		delete(c.lists, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	c.lists[key] = list
+	c.keys = append(c.keys, key)
+}
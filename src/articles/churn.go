@@ -0,0 +1,101 @@
+package articles
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+)
+
+const (
+	DefaultChurnSize = 10
+	MaxChurnSize     = 100
+)
+
+// An article that is in the top lists of both periods
+type Movement struct {
+	Article      string
+	Views        int
+	Rank         int
+	PreviousRank int
+	// Number of places the article went up, negative when it went down
+	Change int
+}
+
+type Churn struct {
+	Period       string
+	ComparedWith string
+	// Articles of the top list of the period that were not in the top list of the earlier period
+	Newcomers []Article
+	// Articles of the top list of the earlier period that are not in the top list of the period, with their
+	// views and rank in the earlier period
+	DropOuts  []Article
+	Movements []Movement
+}
+
+// curl http://localhost:8080/articles/churn/weekly/2023/03
+// curl "http://localhost:8080/articles/churn/daily/20230301?compare=year-ago&n=50"
+// Compares the top n articles of the period with the top n articles of the earlier period
+func GetChurn(p, earlier period.Period, articleFilter filter.Filter, n int) (Churn, error) {
+	if n < 1 || n > MaxChurnSize {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Churn{}, fmt.Errorf(status400+": n must be between 1 and %d", MaxChurnSize)
+	}
+
+	var earlierArticles []Article
+	var earlierErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		earlierArticles, earlierErr = TopArticles(earlier, articleFilter, n, ranking.Ordinal)
+	}()
+	currentArticles, err := TopArticles(p, articleFilter, n, ranking.Ordinal)
+	wg.Wait()
+	if err == nil {
+		err = earlierErr
+	}
+	if err != nil {
+		return Churn{}, err
+	}
+
+	churn := diffTopLists(currentArticles, earlierArticles)
+	churn.Period, churn.ComparedWith = p.Label, earlier.Label
+	return churn, nil
+}
+
+// Splits the articles of two ranked lists into newcomers, drop-outs and the articles of both lists
+// Newcomers and movements are in the order of the current list, drop-outs in the order of the earlier list
+func diffTopLists(current, earlier []Article) Churn {
+	earlierRanks := map[string]int{}
+	for _, article := range earlier {
+		earlierRanks[article.Article] = article.Rank
+	}
+	currentArticles := map[string]bool{}
+
+	churn := Churn{}
+	for _, article := range current {
+		currentArticles[article.Article] = true
+		previousRank, ok := earlierRanks[article.Article]
+		if !ok {
+			churn.Newcomers = append(churn.Newcomers, article)
+			continue
+		}
+		churn.Movements = append(churn.Movements, Movement{
+			Article:      article.Article,
+			Views:        article.Views,
+			Rank:         article.Rank,
+			PreviousRank: previousRank,
+			Change:       previousRank - article.Rank,
+		})
+	}
+	for _, article := range earlier {
+		if !currentArticles[article.Article] {
+			churn.DropOuts = append(churn.DropOuts, article)
+		}
+	}
+	return churn
+}
@@ -0,0 +1,69 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTopLists(t *testing.T) {
+	earlier := []Article{
+		{Article: "ChatGPT", Views: 900, Rank: 1},
+		{Article: "Google_Bard", Views: 800, Rank: 2},
+		{Article: "Albert_Einstein", Views: 700, Rank: 3},
+	}
+	current := []Article{
+		{Article: "Albert_Einstein", Views: 1000, Rank: 1},
+		{Article: "Coronation_of_Charles_III", Views: 950, Rank: 2},
+		{Article: "ChatGPT", Views: 600, Rank: 3},
+	}
+	got := diffTopLists(current, earlier)
+
+	expectedNewcomers := []Article{{Article: "Coronation_of_Charles_III", Views: 950, Rank: 2}}
+	expectedDropOuts := []Article{{Article: "Google_Bard", Views: 800, Rank: 2}}
+	expectedMovements := []Movement{
+		{Article: "Albert_Einstein", Views: 1000, Rank: 1, PreviousRank: 3, Change: 2},
+		{Article: "ChatGPT", Views: 600, Rank: 3, PreviousRank: 1, Change: -2},
+	}
+	if !reflect.DeepEqual(got.Newcomers, expectedNewcomers) {
+		t.Errorf("test 1 failed: got %v want %v", got.Newcomers, expectedNewcomers)
+	}
+	if !reflect.DeepEqual(got.DropOuts, expectedDropOuts) {
+		t.Errorf("test 2 failed: got %v want %v", got.DropOuts, expectedDropOuts)
+	}
+	if !reflect.DeepEqual(got.Movements, expectedMovements) {
+		t.Errorf("test 3 failed: got %v want %v", got.Movements, expectedMovements)
+	}
+}
+
+func TestGetChurnInvalidSize(t *testing.T) {
+	p, err := period.Day("20230301")
+	require.NoError(t, err)
+	_, err = GetChurn(p, p.Previous(), filter.Filter{}, MaxChurnSize+1)
+	require.Error(t, err)
+	assertExpectedOutput(t, 0, err.Error(), "400 Bad Request: n must be between 1 and 100")
+}
+
+func TestGetChurnDaily(t *testing.T) {
+	// the daily lists are read from the cache, so the wikipedia API is not called
+	cached := dailyLists
+	defer func() { dailyLists = cached }()
+	dailyLists = newListCache(maxCachedLists)
+	dailyLists.add("2023/02/28", []Article{{Article: "Main_Page", Views: 5000, Rank: 1}, {Article: "ChatGPT", Views: 300, Rank: 2}})
+	dailyLists.add("2023/03/01", []Article{{Article: "ChatGPT", Views: 6000, Rank: 1}, {Article: "Google_Bard", Views: 500, Rank: 2}})
+
+	p, err := period.Day("20230301")
+	require.NoError(t, err)
+	got, err := GetChurn(p, p.Previous(), filter.Filter{Denylist: true}, 10)
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, got.Period, "2023-03-01")
+	assertExpectedOutput(t, 1, got.ComparedWith, "2023-02-28")
+	assertExpectedOutput(t, 2, len(got.Newcomers), 1)
+	assertExpectedOutput(t, 3, got.Newcomers[0].Article, "Google_Bard")
+	// Main_Page is dropped by the denylist, so it is not a drop-out
+	assertExpectedOutput(t, 4, len(got.DropOuts), 0)
+	assertExpectedOutput(t, 5, got.Movements[0].Change, 0)
+}
@@ -0,0 +1,174 @@
+package articles
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/stats"
+)
+
+const (
+	// Number of articles of the monthly lists of the wikipedia API
+	MaxListSize     = 1000
+	DefaultHeadSize = 100
+	DefaultBins     = 10
+	MaxBins         = 50
+)
+
+// Ranks whose views are returned when no ranks are asked for
+var DefaultRanks = []int{1, 10, 100, 1000}
+
+// Views of the articles of one end of the list
+type Split struct {
+	Articles int
+	Views    int
+	// Percent of the views of the list
+	Share float64
+}
+
+type RankViews struct {
+	Rank int
+	// Views of the article at the rank, i.e. the views an article needs to reach it
+	Views int
+	// Views of the article at the rank and of the articles above it
+	CumulativeViews int
+	CumulativeShare float64
+}
+
+// Articles with at least Lower and less than Upper views, the last bin also has the articles with Upper views
+type Bin struct {
+	Lower    float64
+	Upper    float64
+	Articles int
+	Views    int
+}
+
+type Distribution struct {
+	Period   string
+	Articles int
+	Views    int
+	// Gini coefficient of the views, 0 when every article has the same views and close to 1 when a few articles
+	// get almost all of them
+	Gini      float64
+	Head      Split
+	Tail      Split
+	Ranks     []RankViews
+	Histogram []Bin
+}
+
+// curl http://localhost:8080/articles/distribution/monthly/2023/03
+// curl "http://localhost:8080/articles/distribution/monthly/2023/03?head=10&ranks=1,50,500&bins=20&denylist=true"
+// Returns how the views of the month are spread over the articles of its top list
+// The head is made of the head most viewed articles and the tail of the rest of the list
+func GetDistribution(p period.Period, articleFilter filter.Filter, head int, ranks []int, bins int) (Distribution, error) {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if head < 1 || head > MaxListSize {
+		return Distribution{}, fmt.Errorf(status400+": head must be between 1 and %d", MaxListSize)
+	}
+	for _, rank := range ranks {
+		if rank < 1 || rank > MaxListSize {
+			return Distribution{}, fmt.Errorf(status400+": ranks must be between 1 and %d", MaxListSize)
+		}
+	}
+	if bins < 1 || bins > MaxBins {
+		return Distribution{}, fmt.Errorf(status400+": bins must be between 1 and %d", MaxBins)
+	}
+
+	monthArticles, err := GetTopArticlesOfMonth(p.Start)
+	if err != nil {
+		return Distribution{}, err
+	}
+	distribution := distributionOf(filterArticles(monthArticles, articleFilter, ranking.Ordinal), head, ranks, bins)
+	distribution.Period = p.Label
+	return distribution, nil
+}
+
+// Computes the distribution of a list ranked from the most to the least viewed article
+// Ranks below the end of the list are left out
+func distributionOf(list []Article, head int, ranks []int, bins int) Distribution {
+	values := make([]float64, len(list))
+	total := 0
+	for i, article := range list {
+		values[i] = float64(article.Views)
+		total += article.Views
+	}
+	distribution := Distribution{
+		Articles:  len(list),
+		Views:     total,
+		Gini:      math.Round(stats.Gini(values)*10000) / 10000,
+		Histogram: histogram(values, bins),
+	}
+
+	if head > len(list) {
+		head = len(list)
+	}
+	headViews := 0
+	for _, article := range list[:head] {
+		headViews += article.Views
+	}
+	distribution.Head = Split{Articles: head, Views: headViews}
+	distribution.Tail = Split{Articles: len(list) - head, Views: total - headViews}
+
+	cumulativeViews := make([]int, len(list))
+	for i, article := range list {
+		cumulativeViews[i] = article.Views
+		if i > 0 {
+			cumulativeViews[i] += cumulativeViews[i-1]
+		}
+	}
+	for _, rank := range ranks {
+		if rank > len(list) {
+			continue
+		}
+		distribution.Ranks = append(distribution.Ranks, RankViews{
+			Rank:            rank,
+			Views:           list[rank-1].Views,
+			CumulativeViews: cumulativeViews[rank-1],
+		})
+	}
+
+	if total > 0 {
+		distribution.Head.Share = percent(distribution.Head.Views, total)
+		distribution.Tail.Share = percent(distribution.Tail.Views, total)
+		for i := range distribution.Ranks {
+			distribution.Ranks[i].CumulativeShare = percent(distribution.Ranks[i].CumulativeViews, total)
+		}
+	}
+	return distribution
+}
+
+// Splits the views into bins of equal width on a log scale, from the least to the most viewed article, since a few
+// articles have orders of magnitude more views than the rest of the list
+// Bin edges are rounded to two decimals
+func histogram(values []float64, bins int) []Bin {
+	if len(values) == 0 {
+		return nil
+	}
+	// Articles of the lists have at least one view, the floor only keeps the logarithm defined
+	min, max := math.Max(stats.Min(values), 1), math.Max(stats.Max(values), 1)
+	logMin, logMax := math.Log(min), math.Log(max)
+	width := (logMax - logMin) / float64(bins)
+
+	histogram := make([]Bin, bins)
+	for i := range histogram {
+		histogram[i].Lower = math.Round(math.Exp(logMin+float64(i)*width)*100) / 100
+		histogram[i].Upper = math.Round(math.Exp(logMin+float64(i+1)*width)*100) / 100
+	}
+	histogram[bins-1].Upper = max
+	for _, value := range values {
+		bin := bins - 1
+		if width > 0 {
+			bin = int((math.Log(math.Max(value, 1)) - logMin) / width)
+			if bin >= bins {
+				bin = bins - 1
+			}
+		}
+		histogram[bin].Articles++
+		histogram[bin].Views += int(value)
+	}
+	return histogram
+}
@@ -0,0 +1,76 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistributionOf(t *testing.T) {
+	list := []Article{
+		{Article: "ChatGPT", Views: 1000, Rank: 1},
+		{Article: "Albert_Einstein", Views: 50, Rank: 2},
+		{Article: "YouTube", Views: 5, Rank: 3},
+		{Article: "Cleopatra", Views: 1, Rank: 4},
+	}
+	testCases := []struct {
+		name                 string
+		list                 []Article
+		head                 int
+		ranks                []int
+		bins                 int
+		expectedDistribution Distribution
+	}{
+		{
+			name:  "head of one article and ranks past the end of the list",
+			list:  list,
+			head:  1,
+			ranks: []int{1, 3, 5},
+			bins:  3,
+			expectedDistribution: Distribution{
+				Articles: 4,
+				Views:    1056,
+				Gini:     0.7202,
+				Head:     Split{Articles: 1, Views: 1000, Share: 94.697},
+				Tail:     Split{Articles: 3, Views: 56, Share: 5.303},
+				Ranks: []RankViews{
+					{Rank: 1, Views: 1000, CumulativeViews: 1000, CumulativeShare: 94.697},
+					{Rank: 3, Views: 5, CumulativeViews: 1055, CumulativeShare: 99.9053},
+				},
+				Histogram: []Bin{
+					{Lower: 1, Upper: 10, Articles: 2, Views: 6},
+					{Lower: 10, Upper: 100, Articles: 1, Views: 50},
+					{Lower: 100, Upper: 1000, Articles: 1, Views: 1000},
+				},
+			},
+		},
+		{
+			name:  "head longer than the list",
+			list:  list[2:],
+			head:  100,
+			ranks: nil,
+			bins:  1,
+			expectedDistribution: Distribution{
+				Articles:  2,
+				Views:     6,
+				Gini:      0.3333,
+				Head:      Split{Articles: 2, Views: 6, Share: 100},
+				Tail:      Split{},
+				Histogram: []Bin{{Lower: 1, Upper: 5, Articles: 2, Views: 6}},
+			},
+		},
+		{
+			name:                 "empty list",
+			list:                 nil,
+			head:                 10,
+			ranks:                []int{1},
+			bins:                 10,
+			expectedDistribution: Distribution{},
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := distributionOf(tc.list, tc.head, tc.ranks, tc.bins)
+		if !reflect.DeepEqual(got, tc.expectedDistribution) {
+			t.Errorf("test %d failed: got %+v want %+v", tcNum+1, got, tc.expectedDistribution)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package articles
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+)
+
+const (
+	MaxRankHistoryDays = 366
+	// Number of daily top lists fetched at the same time
+	maxConcurrentLists = 10
+)
+
+// Rank and views of an article in the top list of a day, both null when the article is not in the list
+type RankedDay struct {
+	Timestamp string
+	Rank      *int
+	Views     *int
+}
+
+type RankHistory struct {
+	Article string
+	// Number of days the article was in the top list
+	DaysListed int
+	// Best rank of the article during the period, null when it was never listed
+	BestRank *int
+	Days     []RankedDay
+}
+
+// curl "http://localhost:8080/article/ChatGPT/rank-history?start=20230101&end=20230131"
+// Returns the rank and views of the article in the daily top lists (top 1000) of every day between the two dates
+// (both inclusive)
+func GetRankHistory(article string, startDate, endDate time.Time) (RankHistory, error) {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if startDate.After(endDate) {
+		return RankHistory{}, fmt.Errorf(status400 + ": start date cannot be after end date")
+	}
+	days := period.Period{Start: startDate, End: endDate}.Days()
+	if len(days) > MaxRankHistoryDays {
+		return RankHistory{}, fmt.Errorf(status400+": rank history cannot be longer than %d days", MaxRankHistoryDays)
+	}
+
+	lists, err := getDailyLists(days)
+	if err != nil {
+		return RankHistory{}, err
+	}
+	return rankHistory(article, days, lists), nil
+}
+
+// Fetches the top lists of the days concurrently, a few at a time
+func getDailyLists(days []time.Time) ([][]Article, error) {
+	lists := make([][]Article, len(days))
+	errs := make([]error, len(days))
+	slots := make(chan struct{}, maxConcurrentLists)
+	var wg sync.WaitGroup
+	for i, day := range days {
+		wg.Add(1)
+		go func(i int, day time.Time) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			lists[i], errs[i] = GetTopArticlesByDay(day)
+		}(i, day)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+func rankHistory(article string, days []time.Time, lists [][]Article) RankHistory {
+	history := RankHistory{Article: article, Days: make([]RankedDay, len(days))}
+	for i, day := range days {
+		history.Days[i].Timestamp = period.Timestamp(day)
+		for _, listed := range lists[i] {
+			if listed.Article != article {
+				continue
+			}
+			rank, views := listed.Rank, listed.Views
+			history.Days[i].Rank, history.Days[i].Views = &rank, &views
+			history.DaysListed++
+			if history.BestRank == nil || rank < *history.BestRank {
+				history.BestRank = &rank
+			}
+			break
+		}
+	}
+	return history
+}
@@ -0,0 +1,71 @@
+package articles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRankHistory(t *testing.T) {
+	// the lists are read from the cache, so the wikipedia API is not called
+	cached := dailyLists
+	defer func() { dailyLists = cached }()
+	dailyLists = newListCache(maxCachedLists)
+	dailyLists.add("2023/03/01", []Article{{Article: "Main_Page", Views: 5000, Rank: 1}, {Article: "ChatGPT", Views: 300, Rank: 2}})
+	dailyLists.add("2023/03/02", []Article{{Article: "Main_Page", Views: 5100, Rank: 1}})
+	dailyLists.add("2023/03/03", []Article{{Article: "ChatGPT", Views: 6000, Rank: 1}, {Article: "Main_Page", Views: 4900, Rank: 2}})
+
+	got, err := GetRankHistory("ChatGPT", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, got.DaysListed, 2)
+	assertExpectedOutput(t, 1, *got.BestRank, 1)
+	assertExpectedOutput(t, 2, len(got.Days), 3)
+	assertExpectedOutput(t, 3, got.Days[0].Timestamp, "2023030100")
+	assertExpectedOutput(t, 4, *got.Days[0].Rank, 2)
+	assertExpectedOutput(t, 5, *got.Days[0].Views, 300)
+	require.Nil(t, got.Days[1].Rank)
+	require.Nil(t, got.Days[1].Views)
+	assertExpectedOutput(t, 6, *got.Days[2].Views, 6000)
+}
+
+func TestGetRankHistoryInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name          string
+		start         time.Time
+		end           time.Time
+		expectedError string
+	}{
+		{
+			name:          "start after end",
+			start:         time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+			end:           time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectedError: "400 Bad Request: start date cannot be after end date",
+		},
+		{
+			name:          "more than a year",
+			start:         time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:           time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedError: "400 Bad Request: rank history cannot be longer than 366 days",
+		},
+	}
+	for tcNum, tc := range testCases {
+		_, err := GetRankHistory("ChatGPT", tc.start, tc.end)
+		require.Error(t, err)
+		assertExpectedOutput(t, tcNum, err.Error(), tc.expectedError)
+	}
+}
+
+func TestListCache(t *testing.T) {
+	cache := newListCache(2)
+	cache.add("2023/03/01", []Article{{Article: "A"}})
+	cache.add("2023/03/02", []Article{{Article: "B"}})
+	cache.add("2023/03/03", []Article{{Article: "C"}})
+
+	// the oldest list is dropped when the cache is full
+	_, ok := cache.get("2023/03/01")
+	assertExpectedOutput(t, 0, ok, false)
+	list, ok := cache.get("2023/03/03")
+	assertExpectedOutput(t, 1, ok, true)
+	assertExpectedOutput(t, 2, list[0].Article, "C")
+}
@@ -0,0 +1,77 @@
+package articles
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+)
+
+type ArticleShare struct {
+	Article string
+	Views   int
+	Rank    int
+	// Percent of the views of all the articles of the project over the period
+	Share float64
+	// Percent of the views of the project that go to this article and the articles above it, i.e. the Pareto
+	// curve of the list
+	CumulativeShare float64
+}
+
+// curl "http://localhost:8080/articles/top/monthly/2023/03?share=true"
+// Returns the 10 most viewed articles for a period like GetTopArticles, with the share of each article in the
+// views of the project
+// The top lists of the wikipedia API only count the views of users, so shares are of the user views of the project
+func GetTopArticlesWithShares(p period.Period, articleFilter filter.Filter, method ranking.Method) (string, error) {
+	var projectViews int
+	var projectErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		projectViews, projectErr = pageviews.GetProjectPageviews(p, pageviews.User)
+	}()
+	topArticles, err := TopArticles(p, articleFilter, 10, method)
+	wg.Wait()
+	if err == nil {
+		err = projectErr
+	}
+	if err != nil {
+		return "", err
+	}
+	// if there are no results return empty result set
+	if len(topArticles) == 0 && p.Granularity != period.Monthly {
+		return "", nil
+	}
+
+	jsonResult, err := json.Marshal(withShares(topArticles, projectViews))
+	if err != nil {
+		return "", err
+	}
+	return string(jsonResult), nil
+}
+
+// Adds the share and the cumulative share (in percent, rounded to four decimals) of the articles of a ranked list
+// Shares are 0 when the project has no views
+func withShares(topArticles []Article, projectViews int) []ArticleShare {
+	var shares []ArticleShare
+	cumulativeViews := 0
+	for _, article := range topArticles {
+		cumulativeViews += article.Views
+		share := ArticleShare{Article: article.Article, Views: article.Views, Rank: article.Rank}
+		if projectViews > 0 {
+			share.Share = percent(article.Views, projectViews)
+			share.CumulativeShare = percent(cumulativeViews, projectViews)
+		}
+		shares = append(shares, share)
+	}
+	return shares
+}
+
+func percent(views, total int) float64 {
+	return math.Round(float64(views)/float64(total)*1000000) / 10000
+}
@@ -0,0 +1,44 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithShares(t *testing.T) {
+	topArticles := []Article{
+		{Article: "ChatGPT", Views: 5000, Rank: 1},
+		{Article: "Albert_Einstein", Views: 2500, Rank: 2},
+		{Article: "YouTube", Views: 1, Rank: 3},
+	}
+	testCases := []struct {
+		name           string
+		projectViews   int
+		expectedShares []ArticleShare
+	}{
+		{
+			name:         "shares of the project views",
+			projectViews: 30000,
+			expectedShares: []ArticleShare{
+				{Article: "ChatGPT", Views: 5000, Rank: 1, Share: 16.6667, CumulativeShare: 16.6667},
+				{Article: "Albert_Einstein", Views: 2500, Rank: 2, Share: 8.3333, CumulativeShare: 25},
+				{Article: "YouTube", Views: 1, Rank: 3, Share: 0.0033, CumulativeShare: 25.0033},
+			},
+		},
+		{
+			name:         "project without views",
+			projectViews: 0,
+			expectedShares: []ArticleShare{
+				{Article: "ChatGPT", Views: 5000, Rank: 1},
+				{Article: "Albert_Einstein", Views: 2500, Rank: 2},
+				{Article: "YouTube", Views: 1, Rank: 3},
+			},
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := withShares(topArticles, tc.projectViews)
+		if !reflect.DeepEqual(got, tc.expectedShares) {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got, tc.expectedShares)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package compare
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/redirects"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+const MaxArticles = 20
+
+type Comparison struct {
+	Granularity string
+	Timestamps  []string
+	Articles    []Series
+}
+
+// Views are aligned with the Timestamps of the comparison
+type Series struct {
+	Article string
+	Total   int
+	Rank    int
+	Views   []int
+}
+
+// curl "http://localhost:8080/compare?articles=ChatGPT,Google_Bard&start=20230301&end=20230331&granularity=daily"
+// Fetches the series of every article concurrently and aligns them on the same timestamps
+// Articles without any data in the period get a series of zeros instead of failing the comparison
+// Depending on the mode, articles are replaced by the article they redirect to or merged with their redirects
+// The article of the query is ignored, the query only sets the dates and granularity of the series
+func Compare(articles []string, query pageviews.Query, mode redirects.Mode) (Comparison, error) {
+	if len(articles) == 0 || len(articles) > MaxArticles {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Comparison{}, fmt.Errorf(status400+": between 1 and %d articles can be compared", MaxArticles)
+	}
+
+	timestamps, err := pageviews.Timestamps(query)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	articleTitles := make([][]string, len(articles))
+	errs := make([]error, len(articles))
+	var wg sync.WaitGroup
+	for i, article := range articles {
+		wg.Add(1)
+		go func(i int, article string) {
+			defer wg.Done()
+			articleTitles[i], errs[i] = redirects.Titles(article, mode)
+		}(i, article)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Comparison{}, err
+		}
+	}
+	err = checkDuplicates(articles, articleTitles)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	series := make([]Series, len(articles))
+	for i, titles := range articleTitles {
+		wg.Add(1)
+		go func(i int, titles []string) {
+			defer wg.Done()
+			series[i], errs[i] = getSeries(titles, query, len(timestamps))
+		}(i, titles)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return Comparison{}, err
+		}
+	}
+	rank(series)
+
+	return Comparison{
+		Granularity: query.Granularity,
+		Timestamps:  timestamps,
+		Articles:    series,
+	}, nil
+}
+
+// Rejects articles that are listed twice, or that are the same article once redirects are resolved, since they
+// would be compared with themselves
+func checkDuplicates(articles []string, articleTitles [][]string) error {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	listed := map[string]int{}
+	for i, titles := range articleTitles {
+		j, found := listed[titles[0]]
+		if !found {
+			listed[titles[0]] = i
+			continue
+		}
+		if articles[i] == articles[j] {
+			return fmt.Errorf(status400+": articles must be different, %q is listed more than once", articles[i])
+		}
+		return fmt.Errorf(status400+": articles must be different, %q and %q are both %q", articles[j], articles[i], titles[0])
+	}
+	return nil
+}
+
+// Returns the series of the titles (an article and optionally its redirects), the first title is the article
+func getSeries(titles []string, query pageviews.Query, length int) (Series, error) {
+	items, err := pageviews.GetMergedSeries(titles, query)
+	if err != nil && !utilities.IsNotFound(err) {
+		return Series{}, err
+	}
+
+	views := make([]int, length)
+	total := 0
+	for i, item := range items {
+		views[i] = item.Views
+		total += item.Views
+	}
+	return Series{Article: titles[0], Total: total, Views: views}, nil
+}
+
+// Ranks the series by total views, articles with the same total are ranked alphabetically like in the top lists
+func rank(series []Series) {
+	titles := make([]string, len(series))
+	totals := make([]int, len(series))
+	for i, s := range series {
+		titles[i], totals[i] = s.Article, s.Total
+	}
+	for i, rank := range ranking.Ranks(titles, totals, ranking.Ordinal) {
+		series[i].Rank = rank
+	}
+}
@@ -0,0 +1,51 @@
+package compare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/redirects"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	series := []Series{
+		{Article: "Google_Bard", Total: 120},
+		{Article: "ChatGPT", Total: 900},
+		{Article: "Bing", Total: 120},
+		{Article: "Claude", Total: 0},
+	}
+	rank(series)
+
+	expectedRanks := []int{3, 1, 2, 4}
+	for tcNum, s := range series {
+		if s.Rank != expectedRanks[tcNum] {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, s.Rank, expectedRanks[tcNum])
+		}
+	}
+}
+
+func TestCompareInvalidInput(t *testing.T) {
+	startDate := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	_, err := Compare(nil, pageviews.Query{Start: startDate, End: endDate, Granularity: "daily"}, redirects.None)
+	require.Error(t, err)
+
+	_, err = Compare([]string{"ChatGPT"}, pageviews.Query{Start: endDate, End: startDate, Granularity: "daily"}, redirects.None)
+	require.EqualError(t, err, "400 Bad Request: start date cannot be after end date")
+
+	_, err = Compare([]string{"ChatGPT"}, pageviews.Query{Start: startDate, End: endDate, Granularity: "yearly"}, redirects.None)
+	require.EqualError(t, err, "400 Bad Request: granularity must be hourly, daily or monthly")
+
+	_, err = Compare([]string{"ChatGPT", "Google_Bard", "ChatGPT"}, pageviews.Query{Start: startDate, End: endDate, Granularity: "daily"}, redirects.None)
+	require.EqualError(t, err, `400 Bad Request: articles must be different, "ChatGPT" is listed more than once`)
+
+	source, err := redirects.NewMapSource("en.wikipedia", map[string]string{"Einstein": "Albert_Einstein"})
+	require.NoError(t, err)
+	redirects.SetSource(source)
+	defer redirects.SetSource(nil)
+	_, err = Compare([]string{"Albert_Einstein", "Einstein"}, pageviews.Query{Start: startDate, End: endDate, Granularity: "daily"}, redirects.Resolve)
+	require.EqualError(t, err, `400 Bad Request: articles must be different, "Albert_Einstein" and "Einstein" are both "Albert_Einstein"`)
+}
@@ -3,23 +3,50 @@ package converters
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
 )
 
+// PeriodStart and PeriodEnd are the first and last days (YYYY-MM-DD) of the period of the counts
 type Pageviews struct {
-	Pageviews string
+	Pageviews   string
+	PeriodStart string
+	PeriodEnd   string
+}
+
+// Pageviews of a period along with how they changed since an earlier period
+type ComparedPageviews struct {
+	Pageviews   string
+	Comparison  interface{}
+	PeriodStart string
+	PeriodEnd   string
 }
 
 type TopDayPageviews struct {
-	Pageviews string
-	Timestamp string
+	Pageviews   string
+	Timestamp   string
+	PeriodStart string
+	PeriodEnd   string
+}
+
+type MediaRequests struct {
+	Requests    string
+	PeriodStart string
+	PeriodEnd   string
 }
 
 type Error struct {
 	Error string
 }
 
-func ConvertPageviewsToJson(input int) ([]byte, error) {
+// Returns the first and last days of the period
+func periodDays(p period.Period) (string, string) {
+	return p.Start.Format("2006-01-02"), p.End.Format("2006-01-02")
+}
+
+func ConvertPageviewsToJson(input int, p period.Period) ([]byte, error) {
 	pageviews := &Pageviews{Pageviews: fmt.Sprint(input)}
+	pageviews.PeriodStart, pageviews.PeriodEnd = periodDays(p)
 	res, err := json.Marshal(pageviews)
 	if err != nil {
 		return nil, err
@@ -27,11 +54,22 @@ func ConvertPageviewsToJson(input int) ([]byte, error) {
 	return res, nil
 }
 
-func ConvertTopDayPageviewsToJson(timestamp string, pageviews int) ([]byte, error) {
+func ConvertComparedPageviewsToJson(input int, comparison interface{}, p period.Period) ([]byte, error) {
+	pageviews := &ComparedPageviews{Pageviews: fmt.Sprint(input), Comparison: comparison}
+	pageviews.PeriodStart, pageviews.PeriodEnd = periodDays(p)
+	res, err := json.Marshal(pageviews)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func ConvertTopDayPageviewsToJson(timestamp string, pageviews int, p period.Period) ([]byte, error) {
 	topDayPageviews := &TopDayPageviews{
 		Pageviews: fmt.Sprint(pageviews),
 		Timestamp: timestamp,
 	}
+	topDayPageviews.PeriodStart, topDayPageviews.PeriodEnd = periodDays(p)
 	res, err := json.Marshal(topDayPageviews)
 	if err != nil {
 		return nil, err
@@ -39,6 +77,25 @@ func ConvertTopDayPageviewsToJson(timestamp string, pageviews int) ([]byte, erro
 	return res, nil
 }
 
+func ConvertMediaRequestsToJson(input int, p period.Period) ([]byte, error) {
+	mediaRequests := &MediaRequests{Requests: fmt.Sprint(input)}
+	mediaRequests.PeriodStart, mediaRequests.PeriodEnd = periodDays(p)
+	res, err := json.Marshal(mediaRequests)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Converts results that are already shaped as the response
+func ConvertToJson(input interface{}) ([]byte, error) {
+	res, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func ConvertErrorToJson(input string) ([]byte, error) {
 	error := &Error{Error: input}
 	res, err := json.Marshal(error)
@@ -47,3 +104,43 @@ func ConvertErrorToJson(input string) ([]byte, error) {
 	}
 	return res, nil
 }
+
+type ArticleActivity struct {
+	Pageviews   string
+	Edits       string
+	PeriodStart string
+	PeriodEnd   string
+}
+
+type ProjectActivity struct {
+	Editors     string
+	NewPages    string
+	PeriodStart string
+	PeriodEnd   string
+}
+
+func ConvertArticleActivityToJson(pageviews, edits int, p period.Period) ([]byte, error) {
+	articleActivity := &ArticleActivity{
+		Pageviews: fmt.Sprint(pageviews),
+		Edits:     fmt.Sprint(edits),
+	}
+	articleActivity.PeriodStart, articleActivity.PeriodEnd = periodDays(p)
+	res, err := json.Marshal(articleActivity)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func ConvertProjectActivityToJson(editors, newPages int, p period.Period) ([]byte, error) {
+	projectActivity := &ProjectActivity{
+		Editors:  fmt.Sprint(editors),
+		NewPages: fmt.Sprint(newPages),
+	}
+	projectActivity.PeriodStart, projectActivity.PeriodEnd = periodDays(p)
+	res, err := json.Marshal(projectActivity)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -4,14 +4,35 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
 	"github.com/stretchr/testify/require"
 )
 
+func april(t testing.TB) period.Period {
+	t.Helper()
+	p, err := period.Month("2023", "04")
+	require.NoError(t, err)
+	return p
+}
+
 func TestConvertPageviewsToJson(t *testing.T) {
 	t.Run("convert input to JSON", func(t *testing.T) {
 		pageviews := 485684
-		want := []byte(`{"Pageviews":"485684"}`)
-		got, err := ConvertPageviewsToJson(pageviews)
+		want := []byte(`{"Pageviews":"485684","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertPageviewsToJson(pageviews, april(t))
+		require.NoError(t, err)
+		assertJSON(t, got, want)
+	})
+}
+
+func TestConvertComparedPageviewsToJson(t *testing.T) {
+	t.Run("convert input to JSON", func(t *testing.T) {
+		comparison := struct {
+			Period    string
+			Pageviews int
+		}{Period: "2023-03", Pageviews: 400000}
+		want := []byte(`{"Pageviews":"485684","Comparison":{"Period":"2023-03","Pageviews":400000},"PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertComparedPageviewsToJson(485684, comparison, april(t))
 		require.NoError(t, err)
 		assertJSON(t, got, want)
 	})
@@ -21,8 +42,30 @@ func TestConvertTopDayPageviewsToJson(t *testing.T) {
 	t.Run("convert input to JSON", func(t *testing.T) {
 		pageviews := 30724
 		timestamp := "2023042200"
-		want := []byte(`{"Pageviews":"30724","Timestamp":"2023042200"}`)
-		got, err := ConvertTopDayPageviewsToJson(timestamp, pageviews)
+		want := []byte(`{"Pageviews":"30724","Timestamp":"2023042200","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertTopDayPageviewsToJson(timestamp, pageviews, april(t))
+		require.NoError(t, err)
+		assertJSON(t, got, want)
+	})
+}
+
+func TestConvertMediaRequestsToJson(t *testing.T) {
+	t.Run("convert input to JSON", func(t *testing.T) {
+		want := []byte(`{"Requests":"1024","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertMediaRequestsToJson(1024, april(t))
+		require.NoError(t, err)
+		assertJSON(t, got, want)
+	})
+}
+
+func TestConvertToJson(t *testing.T) {
+	t.Run("convert input to JSON", func(t *testing.T) {
+		input := struct {
+			Article string
+			Views   []int
+		}{Article: "ChatGPT", Views: []int{3, 0, 5}}
+		want := []byte(`{"Article":"ChatGPT","Views":[3,0,5]}`)
+		got, err := ConvertToJson(input)
 		require.NoError(t, err)
 		assertJSON(t, got, want)
 	})
@@ -44,3 +87,21 @@ func assertJSON(t testing.TB, got, want interface{}) {
 		t.Errorf("got %v want %v", got, want)
 	}
 }
+
+func TestConvertArticleActivityToJson(t *testing.T) {
+	t.Run("convert input to JSON", func(t *testing.T) {
+		want := []byte(`{"Pageviews":"485684","Edits":"37","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertArticleActivityToJson(485684, 37, april(t))
+		require.NoError(t, err)
+		assertJSON(t, got, want)
+	})
+}
+
+func TestConvertProjectActivityToJson(t *testing.T) {
+	t.Run("convert input to JSON", func(t *testing.T) {
+		want := []byte(`{"Editors":"39512","NewPages":"16890","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`)
+		got, err := ConvertProjectActivityToJson(39512, 16890, april(t))
+		require.NoError(t, err)
+		assertJSON(t, got, want)
+	})
+}
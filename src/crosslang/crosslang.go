@@ -0,0 +1,178 @@
+package crosslang
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/titles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+const MaxEditions = 50
+
+// Projects are a language code and a wiki family, e.g. en.wikipedia, de.wikipedia or zh-yue.wikipedia
+var projectPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*\.[a-z]+$`)
+
+// An Edition is the article about a concept on one wiki
+type Edition struct {
+	Project string
+	Title   string
+}
+
+type Aggregate struct {
+	Granularity string
+	Timestamps  []string
+	Total       int
+	// Views of all the editions added up, aligned with the Timestamps
+	Views    []int
+	Editions []Series
+}
+
+// Views are aligned with the Timestamps of the aggregate
+type Series struct {
+	Project string
+	Article string
+	Total   int
+	Views   []int
+}
+
+// Parses a project:title pair, e.g. de.wikipedia:Albert_Einstein
+// Only the first colon separates the project, so titles with a namespace like fr.wikipedia:Portail:Physique work
+func ParseEdition(input string) (Edition, error) {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	project, title, found := strings.Cut(strings.TrimSpace(input), ":")
+	if !found || !projectPattern.MatchString(project) || title == "" {
+		return Edition{}, fmt.Errorf(status400+": %q must be a project and a title, e.g. de.wikipedia:Albert_Einstein", input)
+	}
+	title, err := titles.Normalize(project, title)
+	if err != nil {
+		return Edition{}, err
+	}
+	return Edition{Project: project, Title: title}, nil
+}
+
+// curl "http://localhost:8080/crosslang?editions=en.wikipedia:Albert_Einstein,de.wikipedia:Albert_Einstein&start=20230301&end=20230331"
+// Fetches the series of every edition concurrently and adds them up on the same timestamps
+// Editions without any data in the period get a series of zeros instead of failing the aggregate
+// The article and project of the query are ignored, the query only sets the dates and granularity
+func GetAggregate(editions []Edition, query pageviews.Query) (Aggregate, error) {
+	if len(editions) == 0 || len(editions) > MaxEditions {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Aggregate{}, fmt.Errorf(status400+": between 1 and %d editions can be added up", MaxEditions)
+	}
+	timestamps, err := pageviews.Timestamps(query)
+	if err != nil {
+		return Aggregate{}, err
+	}
+
+	series := make([]Series, len(editions))
+	errs := make([]error, len(editions))
+	var wg sync.WaitGroup
+	for i, edition := range editions {
+		wg.Add(1)
+		go func(i int, edition Edition) {
+			defer wg.Done()
+			series[i], errs[i] = getSeries(edition, query, len(timestamps))
+		}(i, edition)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return Aggregate{}, err
+		}
+	}
+
+	aggregate := Aggregate{
+		Granularity: query.Granularity,
+		Timestamps:  timestamps,
+		Views:       make([]int, len(timestamps)),
+		Editions:    series,
+	}
+	for _, edition := range series {
+		aggregate.Total += edition.Total
+		for i, views := range edition.Views {
+			aggregate.Views[i] += views
+		}
+	}
+	return aggregate, nil
+}
+
+func getSeries(edition Edition, query pageviews.Query, length int) (Series, error) {
+	query.Project, query.Article = edition.Project, edition.Title
+	items, err := pageviews.GetSeries(query)
+	if err != nil && !utilities.IsNotFound(err) {
+		return Series{}, err
+	}
+
+	views := make([]int, length)
+	total := 0
+	for i, item := range items {
+		views[i] = item.Views
+		total += item.Views
+	}
+	return Series{Project: edition.Project, Article: edition.Title, Total: total, Views: views}, nil
+}
+
+// A Mapping lists the editions of every concept, so the editions do not have to be spelled out in every request
+type Mapping map[string][]Edition
+
+var (
+	mappingMu sync.RWMutex
+	mapping   Mapping
+)
+
+func SetMapping(m Mapping) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	mapping = m
+}
+
+// Returns the editions of the concept, concepts are matched case-insensitively
+func Editions(concept string) ([]Edition, error) {
+	mappingMu.RLock()
+	defer mappingMu.RUnlock()
+	for name, editions := range mapping {
+		if strings.EqualFold(name, strings.TrimSpace(concept)) {
+			return editions, nil
+		}
+	}
+	status404 := fmt.Sprint(http.StatusNotFound) + " " + http.StatusText(http.StatusNotFound)
+	return nil, fmt.Errorf(status404+": unknown concept %q", concept)
+}
+
+// Reads the interlanguage mapping from a JSON file that maps every concept to its title on every project:
+//
+//	{"Albert Einstein": {"en.wikipedia": "Albert_Einstein", "el.wikipedia": "Άλμπερτ_Αϊνστάιν"}}
+func NewFileMapping(path string) (Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var input map[string]map[string]string
+	err = json.Unmarshal(data, &input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse interlanguage file %s: %w", path, err)
+	}
+
+	m := Mapping{}
+	for concept, projectTitles := range input {
+		for project, title := range projectTitles {
+			edition, err := ParseEdition(project + ":" + title)
+			if err != nil {
+				return nil, fmt.Errorf("invalid edition of %s in interlanguage file %s: %w", concept, path, err)
+			}
+			m[concept] = append(m[concept], edition)
+		}
+		// Map iteration is random, keep the editions in the same order on every run
+		sort.Slice(m[concept], func(i, j int) bool { return m[concept][i].Project < m[concept][j].Project })
+	}
+	return m, nil
+}
@@ -0,0 +1,85 @@
+package crosslang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEdition(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           string
+		expectedEdition Edition
+		expectedError   string
+	}{
+		{
+			name:            "title is normalized",
+			input:           "de.wikipedia:albert Einstein",
+			expectedEdition: Edition{Project: "de.wikipedia", Title: "Albert_Einstein"},
+		},
+		{
+			name:            "title with a namespace",
+			input:           "fr.wikipedia:Portail:Physique",
+			expectedEdition: Edition{Project: "fr.wikipedia", Title: "Portail:Physique"},
+		},
+		{
+			name:          "missing project",
+			input:         "Albert_Einstein",
+			expectedError: `400 Bad Request: "Albert_Einstein" must be a project and a title, e.g. de.wikipedia:Albert_Einstein`,
+		},
+		{
+			name:          "invalid project",
+			input:         "https://de.wikipedia.org:Albert_Einstein",
+			expectedError: `400 Bad Request: "https://de.wikipedia.org:Albert_Einstein" must be a project and a title, e.g. de.wikipedia:Albert_Einstein`,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := ParseEdition(tc.input)
+		if tc.expectedError != "" {
+			require.EqualError(t, err, tc.expectedError, "test %d failed", tcNum+1)
+			continue
+		}
+		require.NoError(t, err, "test %d failed", tcNum+1)
+		require.Equal(t, tc.expectedEdition, got, "test %d failed", tcNum+1)
+	}
+}
+
+func TestNewFileMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interlanguage.json")
+	content := `{"Albert Einstein": {"en.wikipedia": "Albert_Einstein", "de.wikipedia": "Albert Einstein"}}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	m, err := NewFileMapping(path)
+	require.NoError(t, err)
+	SetMapping(m)
+	defer SetMapping(nil)
+
+	editions, err := Editions("albert einstein")
+	require.NoError(t, err)
+	require.Equal(t, []Edition{
+		{Project: "de.wikipedia", Title: "Albert_Einstein"},
+		{Project: "en.wikipedia", Title: "Albert_Einstein"},
+	}, editions)
+
+	_, err = Editions("Isaac Newton")
+	require.EqualError(t, err, `404 Not Found: unknown concept "Isaac Newton"`)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"Albert Einstein": {"wikipedia": "Albert_Einstein"}}`), 0o600))
+	_, err = NewFileMapping(path)
+	require.Error(t, err)
+}
+
+func TestGetAggregateInvalidInput(t *testing.T) {
+	query := pageviews.Query{
+		Start:       time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+		Granularity: pageviews.Daily,
+	}
+	_, err := GetAggregate(nil, query)
+	require.EqualError(t, err, "400 Bad Request: between 1 and 50 editions can be added up")
+}
@@ -0,0 +1,93 @@
+package edits
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+// Move this to the config file
+const baseURL = "https://wikimedia.org/api/rest_v1/metrics"
+const project = "en.wikipedia"
+
+type Items struct {
+	Items []Item
+}
+
+type Item struct {
+	Project     string
+	Granularity string
+	Results     []Result
+}
+
+// The edit metrics share the same response shape, only the name of the counter changes
+type Result struct {
+	Timestamp string
+	Edits     int
+	Editors   int
+	NewPages  int `json:"new_pages"`
+}
+
+// The edit metrics treat the end date as exclusive, so one day is added to the last day of the period
+func buildDateRange(startDate, endDate time.Time) string {
+	return utilities.FormatDate(startDate) + "/" + utilities.FormatDate(endDate.AddDate(0, 0, 1))
+}
+
+func getResults(url string) ([]Result, error) {
+	responseData, err := utilities.CallAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var items Items
+	err = json.Unmarshal(responseData, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	// An article without any edits in the period comes back without items
+	if len(items.Items) == 0 {
+		return nil, nil
+	}
+	return items.Items[0].Results, nil
+}
+
+// Returns the number of edits made to an article between the two dates (both inclusive)
+func GetEditsPerPage(article string, startDate, endDate time.Time) (int, error) {
+	url := fmt.Sprintf("%s/edits/per-page/%s/%s/all-editor-types/daily/%s", baseURL, project, url.PathEscape(article), buildDateRange(startDate, endDate))
+	results, err := getResults(url)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := 0
+	for _, result := range results {
+		sum += result.Edits
+	}
+	return sum, nil
+}
+
+// Returns the number of distinct editors of the project for each month between the two dates
+// Editors are counted per month, so the counts cannot be added up to a total
+func GetEditors(startDate, endDate time.Time) ([]Result, error) {
+	url := fmt.Sprintf("%s/editors/aggregate/%s/all-editor-types/content/all-activity-levels/monthly/%s", baseURL, project, buildDateRange(startDate, endDate))
+	return getResults(url)
+}
+
+// Returns the number of pages created in the project between the two dates (both inclusive)
+func GetNewPages(startDate, endDate time.Time) (int, error) {
+	url := fmt.Sprintf("%s/edited-pages/new/%s/all-editor-types/content/daily/%s", baseURL, project, buildDateRange(startDate, endDate))
+	results, err := getResults(url)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := 0
+	for _, result := range results {
+		sum += result.NewPages
+	}
+	return sum, nil
+}
@@ -0,0 +1,40 @@
+package edits
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildDateRange(t *testing.T) {
+	testCases := []struct {
+		name           string
+		startDate      time.Time
+		endDate        time.Time
+		expectedOutput string
+	}{
+		{
+			name:           "week within a month",
+			startDate:      time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC),
+			endDate:        time.Date(2023, 1, 22, 0, 0, 0, 0, time.UTC),
+			expectedOutput: "20230116/20230123",
+		},
+		{
+			name:           "month (end date rolls over to the next month)",
+			startDate:      time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			endDate:        time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC),
+			expectedOutput: "20230401/20230501",
+		},
+		{
+			name:           "week that ends in the next year",
+			startDate:      time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC),
+			endDate:        time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+			expectedOutput: "20201228/20210104",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := buildDateRange(tc.startDate, tc.endDate)
+		if got != tc.expectedOutput {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got, tc.expectedOutput)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultProject = "en.wikipedia"
+	Main           = "main"
+)
+
+// Canonical namespace names work on every project, localized names and aliases are listed per project
+var canonicalNamespaces = map[string][]string{
+	"media":          {"Media"},
+	"special":        {"Special"},
+	"talk":           {"Talk"},
+	"user":           {"User"},
+	"user talk":      {"User talk"},
+	"project":        {"Project"},
+	"project talk":   {"Project talk"},
+	"file":           {"File", "Image"},
+	"file talk":      {"File talk", "Image talk"},
+	"mediawiki":      {"MediaWiki"},
+	"mediawiki talk": {"MediaWiki talk"},
+	"template":       {"Template"},
+	"template talk":  {"Template talk"},
+	"help":           {"Help"},
+	"help talk":      {"Help talk"},
+	"category":       {"Category"},
+	"category talk":  {"Category talk"},
+	"portal":         {"Portal"},
+	"portal talk":    {"Portal talk"},
+	"draft":          {"Draft"},
+	"draft talk":     {"Draft talk"},
+	"timedtext":      {"TimedText"},
+	"timedtext talk": {"TimedText talk"},
+	"module":         {"Module"},
+	"module talk":    {"Module talk"},
+}
+
+var projectNamespaces = map[string]map[string][]string{
+	"en.wikipedia": {
+		"project":      {"Wikipedia", "WP"},
+		"project talk": {"Wikipedia talk", "WT"},
+	},
+	"de.wikipedia": {
+		"special":       {"Spezial"},
+		"talk":          {"Diskussion"},
+		"user":          {"Benutzer", "Benutzerin"},
+		"user talk":     {"Benutzer Diskussion", "Benutzerin Diskussion"},
+		"project":       {"Wikipedia", "WP"},
+		"project talk":  {"Wikipedia Diskussion"},
+		"file":          {"Datei", "Bild"},
+		"template":      {"Vorlage"},
+		"help":          {"Hilfe"},
+		"category":      {"Kategorie"},
+		"portal":        {"Portal"},
+		"category talk": {"Kategorie Diskussion"},
+	},
+	"fr.wikipedia": {
+		"special":      {"Spécial"},
+		"talk":         {"Discussion"},
+		"user":         {"Utilisateur", "Utilisatrice"},
+		"user talk":    {"Discussion utilisateur", "Discussion utilisatrice"},
+		"project":      {"Wikipédia", "WP"},
+		"project talk": {"Discussion Wikipédia"},
+		"file":         {"Fichier"},
+		"template":     {"Modèle"},
+		"help":         {"Aide"},
+		"category":     {"Catégorie"},
+		"portal":       {"Portail"},
+	},
+}
+
+// Names that can be used in the namespaces filter besides the canonical ones
+var namespaceAliases = map[string]string{
+	"article":        Main,
+	"wikipedia":      "project",
+	"wikipedia talk": "project talk",
+	"image":          "file",
+}
+
+// Pages that are not articles but are listed at the top because of how readers and tools reach the wiki
+var denylist = map[string][]string{
+	"en.wikipedia": {"Main_Page", "-", "Special:Search", "Wikipedia:Featured_pictures", "Special:CreateAccount", "Special:RecentChanges", "Special:Watchlist"},
+	"de.wikipedia": {"Wikipedia:Hauptseite", "-", "Spezial:Suche"},
+	"fr.wikipedia": {"Wikipédia:Accueil_principal", "-", "Spécial:Recherche"},
+}
+
+// A Filter decides which titles of a top list are kept. The zero value keeps everything.
+type Filter struct {
+	Project string
+	// Namespaces to keep, e.g. main or portal. All namespaces are kept if empty.
+	Namespaces []string
+	// Titles to drop
+	Exclude []string
+	// Drop the known noise pages of the project
+	Denylist bool
+}
+
+// Returns an error if any of the namespaces of the filter is not known
+func (f Filter) Validate() error {
+	for _, namespace := range f.Namespaces {
+		namespace = resolveNamespace(namespace)
+		if _, ok := canonicalNamespaces[namespace]; !ok && namespace != Main {
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			return fmt.Errorf(status400+": unknown namespace %q", namespace)
+		}
+	}
+	return nil
+}
+
+func (f Filter) IsEmpty() bool {
+	return len(f.Namespaces) == 0 && len(f.Exclude) == 0 && !f.Denylist
+}
+
+// Returns true if the title passes the filter
+func (f Filter) Allows(title string) bool {
+	for _, excluded := range f.Exclude {
+		if sameTitle(excluded, title) {
+			return false
+		}
+	}
+	if f.Denylist {
+		for _, denied := range denylist[f.project()] {
+			if sameTitle(denied, title) {
+				return false
+			}
+		}
+	}
+	if len(f.Namespaces) == 0 {
+		return true
+	}
+	namespace := Namespace(f.project(), title)
+	for _, allowed := range f.Namespaces {
+		if resolveNamespace(allowed) == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) project() string {
+	if f.Project == "" {
+		return DefaultProject
+	}
+	return f.Project
+}
+
+// Returns the canonical name of the namespace of the title (e.g. special for Special:Search), or main
+// for articles. Titles with a colon that does not follow a namespace, like XXX:_Return_of_Xander_Cage,
+// are articles.
+func Namespace(project, title string) string {
+	prefix, _, found := strings.Cut(title, ":")
+	if !found {
+		return Main
+	}
+	prefix = normalizeNamespace(prefix)
+	for namespace, names := range projectNamespaces[project] {
+		for _, name := range names {
+			if normalizeNamespace(name) == prefix {
+				return namespace
+			}
+		}
+	}
+	for namespace, names := range canonicalNamespaces {
+		for _, name := range names {
+			if normalizeNamespace(name) == prefix {
+				return namespace
+			}
+		}
+	}
+	return Main
+}
+
+// Returns the canonical name of a namespace requested in the filter
+func resolveNamespace(name string) string {
+	name = normalizeNamespace(name)
+	if canonical, ok := namespaceAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// Namespace names are case-insensitive and use spaces and underscores interchangeably
+func normalizeNamespace(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.ReplaceAll(name, "_", " ")))
+}
+
+// The first letter of titles is case-insensitive, and titles use spaces and underscores interchangeably
+func sameTitle(a, b string) bool {
+	a = strings.ReplaceAll(a, " ", "_")
+	b = strings.ReplaceAll(b, " ", "_")
+	firstA, sizeA := utf8.DecodeRuneInString(a)
+	firstB, sizeB := utf8.DecodeRuneInString(b)
+	return unicode.ToUpper(firstA) == unicode.ToUpper(firstB) && a[sizeA:] == b[sizeB:]
+}
@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace(t *testing.T) {
+	testCases := []struct {
+		name           string
+		project        string
+		title          string
+		expectedOutput string
+	}{
+		{
+			name:           "article",
+			project:        "en.wikipedia",
+			title:          "ChatGPT",
+			expectedOutput: Main,
+		},
+		{
+			name:           "article with a colon in the title",
+			project:        "en.wikipedia",
+			title:          "XXX:_Return_of_Xander_Cage",
+			expectedOutput: Main,
+		},
+		{
+			name:           "special page",
+			project:        "en.wikipedia",
+			title:          "Special:Search",
+			expectedOutput: "special",
+		},
+		{
+			name:           "project namespace of the English wikipedia",
+			project:        "en.wikipedia",
+			title:          "Wikipedia:Featured_pictures",
+			expectedOutput: "project",
+		},
+		{
+			name:           "talk namespace with underscores",
+			project:        "en.wikipedia",
+			title:          "User_talk:Jimbo_Wales",
+			expectedOutput: "user talk",
+		},
+		{
+			name:           "alias of the file namespace",
+			project:        "en.wikipedia",
+			title:          "Image:Example.jpg",
+			expectedOutput: "file",
+		},
+		{
+			name:           "localized namespace of the German wikipedia",
+			project:        "de.wikipedia",
+			title:          "Spezial:Suche",
+			expectedOutput: "special",
+		},
+		{
+			name:           "canonical namespace works on every project",
+			project:        "de.wikipedia",
+			title:          "Portal:Fußball",
+			expectedOutput: "portal",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := Namespace(tc.project, tc.title)
+		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	testCases := []struct {
+		name           string
+		filter         Filter
+		title          string
+		expectedOutput bool
+	}{
+		{
+			name:           "empty filter allows everything",
+			filter:         Filter{},
+			title:          "Main_Page",
+			expectedOutput: true,
+		},
+		{
+			name:           "denylist drops the main page",
+			filter:         Filter{Denylist: true},
+			title:          "Main_Page",
+			expectedOutput: false,
+		},
+		{
+			name:           "denylist drops the dash page",
+			filter:         Filter{Denylist: true},
+			title:          "-",
+			expectedOutput: false,
+		},
+		{
+			name:           "denylist keeps articles",
+			filter:         Filter{Denylist: true},
+			title:          "ChatGPT",
+			expectedOutput: true,
+		},
+		{
+			name:           "excluded titles match regardless of spaces and first letter case",
+			filter:         Filter{Exclude: []string{"the Last of Us"}},
+			title:          "The_Last_of_Us",
+			expectedOutput: false,
+		},
+		{
+			name:           "main namespace drops special pages",
+			filter:         Filter{Namespaces: []string{"main"}},
+			title:          "Special:Search",
+			expectedOutput: false,
+		},
+		{
+			name:           "wikipedia is an alias of the project namespace",
+			filter:         Filter{Namespaces: []string{"Wikipedia"}},
+			title:          "Wikipedia:Featured_pictures",
+			expectedOutput: true,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := tc.filter.Allows(tc.title)
+		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+// Every kind of entry of the denylist of every project
+func TestDenylist(t *testing.T) {
+	testCases := []struct {
+		name           string
+		project        string
+		title          string
+		expectedOutput bool
+	}{
+		{
+			name:           "main page",
+			project:        "en.wikipedia",
+			title:          "Main_Page",
+			expectedOutput: false,
+		},
+		{
+			name:           "dash page of requests without a title",
+			project:        "en.wikipedia",
+			title:          "-",
+			expectedOutput: false,
+		},
+		{
+			name:           "search page",
+			project:        "en.wikipedia",
+			title:          "Special:Search",
+			expectedOutput: false,
+		},
+		{
+			name:           "account creation page",
+			project:        "en.wikipedia",
+			title:          "Special:CreateAccount",
+			expectedOutput: false,
+		},
+		{
+			name:           "recent changes page",
+			project:        "en.wikipedia",
+			title:          "Special:RecentChanges",
+			expectedOutput: false,
+		},
+		{
+			name:           "watchlist page",
+			project:        "en.wikipedia",
+			title:          "Special:Watchlist",
+			expectedOutput: false,
+		},
+		{
+			name:           "featured pictures page",
+			project:        "en.wikipedia",
+			title:          "Wikipedia:Featured_pictures",
+			expectedOutput: false,
+		},
+		{
+			name:           "entries match regardless of spaces",
+			project:        "en.wikipedia",
+			title:          "Wikipedia:Featured pictures",
+			expectedOutput: false,
+		},
+		{
+			name:           "article with the same name as a page of another project",
+			project:        "en.wikipedia",
+			title:          "Spezial:Suche",
+			expectedOutput: true,
+		},
+		{
+			name:           "Undefined is an article",
+			project:        "en.wikipedia",
+			title:          "Undefined",
+			expectedOutput: true,
+		},
+		{
+			name:           "main page of the German wikipedia",
+			project:        "de.wikipedia",
+			title:          "Wikipedia:Hauptseite",
+			expectedOutput: false,
+		},
+		{
+			name:           "dash page of the German wikipedia",
+			project:        "de.wikipedia",
+			title:          "-",
+			expectedOutput: false,
+		},
+		{
+			name:           "search page of the German wikipedia",
+			project:        "de.wikipedia",
+			title:          "Spezial:Suche",
+			expectedOutput: false,
+		},
+		{
+			name:           "main page of the French wikipedia",
+			project:        "fr.wikipedia",
+			title:          "Wikipédia:Accueil_principal",
+			expectedOutput: false,
+		},
+		{
+			name:           "dash page of the French wikipedia",
+			project:        "fr.wikipedia",
+			title:          "-",
+			expectedOutput: false,
+		},
+		{
+			name:           "search page of the French wikipedia",
+			project:        "fr.wikipedia",
+			title:          "Spécial:Recherche",
+			expectedOutput: false,
+		},
+		{
+			name:           "project without a denylist",
+			project:        "es.wikipedia",
+			title:          "Main_Page",
+			expectedOutput: true,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := Filter{Project: tc.project, Denylist: true}.Allows(tc.title)
+		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Filter{Namespaces: []string{"main", "Portal", "user_talk", "wikipedia"}}.Validate())
+	require.EqualError(t, Filter{Namespaces: []string{"articles"}}.Validate(), `400 Bad Request: unknown namespace "articles"`)
+}
+
+func assertExpectedOutput(t testing.TB, testNum int, got, want interface{}) {
+	t.Helper()
+	if got != want {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
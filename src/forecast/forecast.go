@@ -0,0 +1,197 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/stats"
+)
+
+const (
+	// Length of the weekly cycle of daily views
+	Season         = 7
+	DefaultHorizon = 30
+	MaxHorizon     = 365
+	DefaultHistory = 8 * Season
+	MaxHistory     = 3 * 365
+	// Number of standard deviations on each side of the forecast for 95% prediction intervals
+	z95 = 1.96
+)
+
+// Values tried for each of the smoothing parameters when the model is fitted
+var smoothingGrid = []float64{0.01, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Additive Holt-Winters model: every value is a level, plus a trend per step, plus a seasonal component
+type Model struct {
+	// Smoothing parameters of the level, the trend and the seasonal components
+	Alpha float64
+	Beta  float64
+	Gamma float64
+	// Standard deviation of the one step ahead errors on the fitted values
+	Sigma    float64
+	level    float64
+	trend    float64
+	seasonal []float64
+	// Number of fitted values, used to find the seasonal component of the forecasts
+	fitted int
+}
+
+type Prediction struct {
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+type Point struct {
+	Timestamp string
+	Views     float64
+	Lower     float64
+	Upper     float64
+}
+
+type Forecast struct {
+	Article string
+	// First and last day of the data the model was fitted on
+	HistoryStart string
+	HistoryEnd   string
+	Alpha        float64
+	Beta         float64
+	Gamma        float64
+	Forecasts    []Point
+}
+
+// curl "http://localhost:8080/article/ChatGPT/forecast?horizon=30&history=56"
+// Fits a Holt-Winters model with a weekly cycle on the daily views of the last history days of data and forecasts
+// the views of the horizon days after them, with 95% prediction intervals
+func GetForecast(titles []string, horizon, history int, now time.Time) (Forecast, error) {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if horizon < 1 || horizon > MaxHorizon {
+		return Forecast{}, fmt.Errorf(status400+": horizon must be between 1 and %d days", MaxHorizon)
+	}
+	if history < 2*Season || history > MaxHistory {
+		return Forecast{}, fmt.Errorf(status400+": history must be between %d and %d days", 2*Season, MaxHistory)
+	}
+
+	lastDay := period.LastCompleteDay(now)
+	firstDay := lastDay.AddDate(0, 0, 1-history)
+	series, err := pageviews.GetMergedSeries(titles, pageviews.Query{Start: firstDay, End: lastDay, Granularity: pageviews.Daily})
+	if err != nil {
+		return Forecast{}, err
+	}
+	values := make([]float64, len(series))
+	for i, item := range series {
+		values[i] = float64(item.Views)
+	}
+	model, err := Fit(values, Season)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	result := Forecast{
+		Article:      titles[0],
+		HistoryStart: period.Timestamp(firstDay),
+		HistoryEnd:   period.Timestamp(lastDay),
+		Alpha:        model.Alpha,
+		Beta:         model.Beta,
+		Gamma:        model.Gamma,
+	}
+	for i, prediction := range model.Predict(horizon) {
+		result.Forecasts = append(result.Forecasts, Point{
+			Timestamp: period.Timestamp(lastDay.AddDate(0, 0, i+1)),
+			Views:     prediction.Value,
+			Lower:     prediction.Lower,
+			Upper:     prediction.Upper,
+		})
+	}
+	return result, nil
+}
+
+// Fits the model on the values, picking the smoothing parameters with the smallest one step ahead errors
+// At least two seasons of values are needed to estimate the initial trend
+func Fit(values []float64, season int) (Model, error) {
+	if season < 2 || len(values) < 2*season {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Model{}, fmt.Errorf(status400+": at least %d values are needed to fit a cycle of %d values", 2*season, season)
+	}
+	var best Model
+	bestSSE := math.Inf(1)
+	for _, alpha := range smoothingGrid {
+		for _, beta := range smoothingGrid {
+			for _, gamma := range smoothingGrid {
+				model, sse := smooth(values, season, alpha, beta, gamma)
+				if sse < bestSSE {
+					best, bestSSE = model, sse
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+// Runs the smoothing equations over the values and returns the final state of the model and the sum of the
+// squared one step ahead errors
+func smooth(values []float64, season int, alpha, beta, gamma float64) (Model, float64) {
+	// The means of the first two seasons set the trend, the first season sets the level at its last value and the
+	// seasonal components
+	firstMean, secondMean := stats.Mean(values[:season]), stats.Mean(values[season:2*season])
+	trend := (secondMean - firstMean) / float64(season)
+	middle := float64(season-1) / 2
+	model := Model{
+		Alpha:    alpha,
+		Beta:     beta,
+		Gamma:    gamma,
+		level:    firstMean + middle*trend,
+		trend:    trend,
+		seasonal: make([]float64, season),
+		fitted:   len(values),
+	}
+	for i := 0; i < season; i++ {
+		model.seasonal[i] = values[i] - (firstMean + (float64(i)-middle)*trend)
+	}
+
+	sse := 0.0
+	for t := season; t < len(values); t++ {
+		phase := t % season
+		err := values[t] - (model.level + model.trend + model.seasonal[phase])
+		sse += err * err
+
+		level := alpha*(values[t]-model.seasonal[phase]) + (1-alpha)*(model.level+model.trend)
+		model.trend = beta*(level-model.level) + (1-beta)*model.trend
+		model.level = level
+		model.seasonal[phase] = gamma*(values[t]-level) + (1-gamma)*model.seasonal[phase]
+	}
+	model.Sigma = math.Sqrt(sse / float64(len(values)-season))
+	return model, sse
+}
+
+// Returns the forecasts of the horizon values after the fitted ones
+// The prediction intervals widen with every step, since the errors of the level, trend and seasonal components
+// add up. Views cannot be negative, so the forecasts and the intervals are cut at 0.
+func (m Model) Predict(horizon int) []Prediction {
+	season := len(m.seasonal)
+	predictions := make([]Prediction, horizon)
+	// Variance of the h steps ahead error, in multiples of Sigma squared: 1 plus the sum of c(j)^2 for j < h
+	variance := 1.0
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			j := h - 1
+			c := m.Alpha * (1 + float64(j)*m.Beta)
+			if j%season == 0 {
+				c += m.Gamma
+			}
+			variance += c * c
+		}
+		value := m.level + float64(h)*m.trend + m.seasonal[(m.fitted-1+h)%season]
+		margin := z95 * m.Sigma * math.Sqrt(variance)
+		predictions[h-1] = Prediction{
+			Value: math.Max(value, 0),
+			Lower: math.Max(value-margin, 0),
+			Upper: math.Max(value+margin, 0),
+		}
+	}
+	return predictions
+}
@@ -0,0 +1,108 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Weekly pattern of daily views: quieter weekends
+var week = []float64{100, 110, 105, 100, 95, 50, 40}
+
+func TestFit(t *testing.T) {
+	testCases := []struct {
+		name                string
+		trend               float64
+		expectedPredictions []float64
+	}{
+		{
+			name:                "the weekly pattern repeats",
+			trend:               0,
+			expectedPredictions: []float64{100, 110, 105, 100, 95, 50, 40, 100},
+		},
+		{
+			name:                "the trend continues",
+			trend:               2,
+			expectedPredictions: []float64{156, 168, 165, 162, 159, 116, 108, 170},
+		},
+	}
+	for tcNum, tc := range testCases {
+		// four weeks of history
+		values := make([]float64, 28)
+		for i := range values {
+			values[i] = week[i%7] + tc.trend*float64(i)
+		}
+		model, err := Fit(values, Season)
+		require.NoError(t, err)
+		assertFloat(t, tcNum, model.Sigma, 0)
+		for i, prediction := range model.Predict(len(tc.expectedPredictions)) {
+			assertFloat(t, tcNum, prediction.Value, tc.expectedPredictions[i])
+			assertFloat(t, tcNum, prediction.Lower, tc.expectedPredictions[i])
+			assertFloat(t, tcNum, prediction.Upper, tc.expectedPredictions[i])
+		}
+	}
+}
+
+func TestPredictIntervals(t *testing.T) {
+	// the same weekly pattern with alternating noise
+	values := make([]float64, 56)
+	for i := range values {
+		values[i] = week[i%7] + 10*float64(i%2*2-1)
+	}
+	model, err := Fit(values, Season)
+	require.NoError(t, err)
+	require.Greater(t, model.Sigma, 0.0)
+
+	predictions := model.Predict(14)
+	for i, prediction := range predictions {
+		require.LessOrEqual(t, prediction.Lower, prediction.Value)
+		require.GreaterOrEqual(t, prediction.Upper, prediction.Value)
+		require.GreaterOrEqual(t, prediction.Lower, 0.0)
+		if i > 0 {
+			// intervals never get narrower further in the future
+			require.GreaterOrEqual(t, prediction.Upper-prediction.Value, predictions[i-1].Upper-predictions[i-1].Value-1e-9)
+		}
+	}
+}
+
+func TestFitTooFewValues(t *testing.T) {
+	_, err := Fit(week, Season)
+	require.Error(t, err)
+	require.Equal(t, "400 Bad Request: at least 14 values are needed to fit a cycle of 7 values", err.Error())
+}
+
+func TestGetForecastInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name          string
+		horizon       int
+		history       int
+		expectedError string
+	}{
+		{
+			name:          "horizon of 0 days",
+			horizon:       0,
+			history:       DefaultHistory,
+			expectedError: "400 Bad Request: horizon must be between 1 and 365 days",
+		},
+		{
+			name:          "history shorter than two weeks",
+			horizon:       DefaultHorizon,
+			history:       10,
+			expectedError: "400 Bad Request: history must be between 14 and 1095 days",
+		},
+	}
+	for tcNum, tc := range testCases {
+		_, err := GetForecast([]string{"ChatGPT"}, tc.horizon, tc.history, time.Now())
+		require.Error(t, err, "test %d failed", tcNum+1)
+		require.Equal(t, tc.expectedError, err.Error(), "test %d failed", tcNum+1)
+	}
+}
+
+func assertFloat(t testing.TB, testNum int, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package groups
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/articles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/titles"
+)
+
+const (
+	DefaultSize = 10
+	MaxSize     = 100
+)
+
+// A Source knows which groups (categories or topics, e.g. Sports) articles belong to
+type Source interface {
+	// Returns the groups of the article, an article can belong to any number of groups
+	Groups(article string) []string
+	// Returns the name of the group the way the source spells it, and false if there is no such group
+	// Names are matched case-insensitively
+	Lookup(name string) (string, bool)
+}
+
+var (
+	sourceMu sync.RWMutex
+	source   Source
+)
+
+// Sets the source of the groups, a nil source disables the group endpoints
+func SetSource(s Source) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	source = s
+}
+
+func getSource() (Source, error) {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+	if source == nil {
+		status404 := fmt.Sprint(http.StatusNotFound) + " " + http.StatusText(http.StatusNotFound)
+		return nil, fmt.Errorf(status404 + ": no groups are configured")
+	}
+	return source, nil
+}
+
+type GroupViews struct {
+	Group string
+	Views int
+	// Number of articles of the group in the top lists of the period
+	Articles int
+	Rank     int
+}
+
+type GroupArticles struct {
+	Group    string
+	Articles []articles.Article
+}
+
+// curl http://localhost:8080/groups/top/monthly/2023/03
+// Returns the views of every group over the period, from the most to the least viewed
+// Only the articles in the top lists of the period count, an article of several groups counts for each of them
+func GetGroupViews(p period.Period, articleFilter filter.Filter, method ranking.Method) ([]GroupViews, error) {
+	s, err := getSource()
+	if err != nil {
+		return nil, err
+	}
+	articleViews, err := articles.GetPeriodViews(p, articleFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankGroups(articleViews, s, method), nil
+}
+
+// Adds up the views of the articles of every group and ranks the groups
+func rankGroups(articleViews map[string]int, s Source, method ranking.Method) []GroupViews {
+	views := map[string]int{}
+	counts := map[string]int{}
+	for article, articleViews := range articleViews {
+		for _, group := range s.Groups(article) {
+			views[group] += articleViews
+			counts[group]++
+		}
+	}
+
+	var result []GroupViews
+	for _, entry := range ranking.Rank(views, method) {
+		result = append(result, GroupViews{Group: entry.Title, Views: entry.Views, Articles: counts[entry.Title], Rank: entry.Rank})
+	}
+	return result
+}
+
+// curl "http://localhost:8080/groups/Sports/top/monthly/2023/03?n=20"
+// Returns the n most viewed articles of the group over the period
+func GetTopArticlesOfGroup(group string, p period.Period, articleFilter filter.Filter, n int, method ranking.Method) (GroupArticles, error) {
+	if n < 1 || n > MaxSize {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return GroupArticles{}, fmt.Errorf(status400+": n must be between 1 and %d", MaxSize)
+	}
+	s, err := getSource()
+	if err != nil {
+		return GroupArticles{}, err
+	}
+	name, ok := s.Lookup(group)
+	if !ok {
+		status404 := fmt.Sprint(http.StatusNotFound) + " " + http.StatusText(http.StatusNotFound)
+		return GroupArticles{}, fmt.Errorf(status404+": unknown group %q", group)
+	}
+	articleViews, err := articles.GetPeriodViews(p, articleFilter)
+	if err != nil {
+		return GroupArticles{}, err
+	}
+
+	return GroupArticles{Group: name, Articles: rankGroupArticles(name, articleViews, s, n, method)}, nil
+}
+
+// Ranks the articles of the group and returns the n most viewed
+func rankGroupArticles(group string, articleViews map[string]int, s Source, n int, method ranking.Method) []articles.Article {
+	groupViews := map[string]int{}
+	for article, views := range articleViews {
+		for _, articleGroup := range s.Groups(article) {
+			if articleGroup == group {
+				groupViews[article] = views
+				break
+			}
+		}
+	}
+	var result []articles.Article
+	for _, entry := range ranking.Rank(groupViews, method).Top(n) {
+		result = append(result, articles.Article{Article: entry.Title, Views: entry.Views, Rank: entry.Rank})
+	}
+	return result
+}
+
+// FileSource reads the groups from a local file, so no category service is needed:
+//
+// a JSON file maps every group to its articles
+//
+//	{"Sports": ["Lionel_Messi", "FIFA_World_Cup"], "Science": ["Albert_Einstein"]}
+//
+// a CSV file has an article and a group on every line, with an optional article,group header
+//
+//	article,group
+//	Lionel_Messi,Sports
+type FileSource struct {
+	groups map[string][]string
+	// Group names by their lower case name
+	names map[string]string
+}
+
+// Reads a .json or .csv file
+func NewFileSource(path string) (*FileSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mapping map[string][]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(file).Decode(&mapping)
+	case ".csv":
+		mapping, err = readCSV(file)
+	default:
+		return nil, fmt.Errorf("groups file %s must be a .json or .csv file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse groups file %s: %w", path, err)
+	}
+	return NewMapSource(mapping)
+}
+
+func readCSV(input io.Reader) (map[string][]string, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	mapping := map[string][]string{}
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "article") && strings.EqualFold(record[1], "group") {
+			continue
+		}
+		mapping[record[1]] = append(mapping[record[1]], record[0])
+	}
+	return mapping, nil
+}
+
+// Builds a source from a map of groups to their articles
+// Titles are normalized, so "Lionel Messi" and "Lionel_Messi" are the same article
+func NewMapSource(mapping map[string][]string) (*FileSource, error) {
+	s := &FileSource{groups: map[string][]string{}, names: map[string]string{}}
+	for group, groupArticles := range mapping {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		s.names[strings.ToLower(group)] = group
+		for _, article := range groupArticles {
+			article, err := titles.Normalize(filter.DefaultProject, article)
+			if err != nil {
+				return nil, err
+			}
+			s.groups[article] = append(s.groups[article], group)
+		}
+	}
+	// An article listed twice in a group belongs to it once
+	for article, articleGroups := range s.groups {
+		sort.Strings(articleGroups)
+		unique := articleGroups[:1]
+		for _, group := range articleGroups[1:] {
+			if group != unique[len(unique)-1] {
+				unique = append(unique, group)
+			}
+		}
+		s.groups[article] = unique
+	}
+	return s, nil
+}
+
+func (s *FileSource) Groups(article string) []string {
+	return s.groups[article]
+}
+
+func (s *FileSource) Lookup(name string) (string, bool) {
+	group, ok := s.names[strings.ToLower(strings.TrimSpace(name))]
+	return group, ok
+}
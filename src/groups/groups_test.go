@@ -0,0 +1,111 @@
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/articles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/stretchr/testify/require"
+)
+
+func testSource(t *testing.T) *FileSource {
+	t.Helper()
+	s, err := NewMapSource(map[string][]string{
+		"Sports":    {"Lionel Messi", "FIFA_World_Cup", "Lionel_Messi"},
+		"Science":   {"Albert_Einstein"},
+		"Argentina": {"Lionel_Messi"},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestRankGroups(t *testing.T) {
+	articleViews := map[string]int{
+		"Lionel_Messi":    500,
+		"FIFA_World_Cup":  300,
+		"Albert_Einstein": 900,
+		"ChatGPT":         1000,
+	}
+	got := rankGroups(articleViews, testSource(t), ranking.Ordinal)
+	expected := []GroupViews{
+		{Group: "Science", Views: 900, Articles: 1, Rank: 1},
+		{Group: "Sports", Views: 800, Articles: 2, Rank: 2},
+		{Group: "Argentina", Views: 500, Articles: 1, Rank: 3},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v want %v", got, expected)
+	}
+}
+
+func TestRankGroupArticles(t *testing.T) {
+	articleViews := map[string]int{
+		"Lionel_Messi":    500,
+		"FIFA_World_Cup":  300,
+		"Albert_Einstein": 900,
+	}
+	got := rankGroupArticles("Sports", articleViews, testSource(t), 1, ranking.Ordinal)
+	expected := []articles.Article{{Article: "Lionel_Messi", Views: 500, Rank: 1}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v want %v", got, expected)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+	}{
+		{
+			name:     "JSON file",
+			fileName: "groups.json",
+			content:  `{"Sports": ["Lionel_Messi", "FIFA World Cup"]}`,
+		},
+		{
+			name:     "CSV file with a header",
+			fileName: "groups.csv",
+			content:  "article,group\nLionel_Messi,Sports\nFIFA World Cup, Sports\n",
+		},
+		{
+			name:     "CSV file without a header",
+			fileName: "groups.csv",
+			content:  "Lionel_Messi,Sports\nFIFA_World_Cup,Sports\n",
+		},
+	}
+	for tcNum, tc := range testCases {
+		path := filepath.Join(t.TempDir(), tc.fileName)
+		require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+		s, err := NewFileSource(path)
+		require.NoError(t, err, "test %d failed", tcNum+1)
+		require.Equal(t, []string{"Sports"}, s.Groups("FIFA_World_Cup"), "test %d failed", tcNum+1)
+		name, ok := s.Lookup("sports")
+		require.True(t, ok, "test %d failed", tcNum+1)
+		require.Equal(t, "Sports", name, "test %d failed", tcNum+1)
+	}
+
+	path := filepath.Join(t.TempDir(), "groups.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Sports"), 0o600))
+	_, err := NewFileSource(path)
+	require.Error(t, err)
+}
+
+func TestGetTopArticlesOfGroupErrors(t *testing.T) {
+	p, err := period.Month("2023", "03")
+	require.NoError(t, err)
+
+	SetSource(nil)
+	_, err = GetTopArticlesOfGroup("Sports", p, filter.Filter{}, DefaultSize, ranking.Ordinal)
+	require.EqualError(t, err, "404 Not Found: no groups are configured")
+
+	SetSource(testSource(t))
+	defer SetSource(nil)
+	_, err = GetTopArticlesOfGroup("Cooking", p, filter.Filter{}, DefaultSize, ranking.Ordinal)
+	require.EqualError(t, err, `404 Not Found: unknown group "Cooking"`)
+	_, err = GetTopArticlesOfGroup("Sports", p, filter.Filter{}, 0, ranking.Ordinal)
+	require.EqualError(t, err, "400 Bad Request: n must be between 1 and 100")
+}
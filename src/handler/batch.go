@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/converters"
+)
+
+const (
+	maxBatchSize        = 100
+	maxBatchConcurrency = 8
+)
+
+// A sub-query is the path (and query string) of any GET route of the API
+type BatchQuery struct {
+	Path string
+}
+
+// Result holds the response of a successful sub-query, Error the error of a failed one
+// PeriodStart and PeriodEnd are the first and last days of the period of the route, as in the X-Period-Start and
+// X-Period-End headers of single requests, for the results that do not have them already (e.g. lists)
+type BatchResult struct {
+	Path        string
+	Status      int
+	PeriodStart string          `json:",omitempty"`
+	PeriodEnd   string          `json:",omitempty"`
+	Result      json.RawMessage `json:",omitempty"`
+	Error       string          `json:",omitempty"`
+}
+
+// Keeps the response of a sub-query in memory
+type batchResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchResponse() *batchResponse {
+	return &batchResponse{header: http.Header{}, status: http.StatusOK}
+}
+
+func (b *batchResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *batchResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *batchResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+// curl -X POST http://localhost:8080/batch -d '[{"Path": "/article/Albert_Einstein/monthly/2023/04"}, {"Path": "/articles/top/weekly/2023/03"}]'
+// Runs every sub-query through the router in memory, so they are served by the same handlers as single requests
+// A failed sub-query is reported in its own result and does not fail the whole batch
+// The calls of all the sub-queries to the wikipedia API share the limit of utilities.CallAPI
+func BatchHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var queries []BatchQuery
+		err := json.NewDecoder(r.Body).Decode(&queries)
+		if err != nil {
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			writeError(w, fmt.Errorf(status400+": request body must be a JSON array of sub-queries: %s", err.Error()))
+			return
+		}
+		if len(queries) > maxBatchSize {
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			writeError(w, fmt.Errorf(status400+": a batch cannot contain more than %d sub-queries", maxBatchSize))
+			return
+		}
+
+		results := make([]BatchResult, len(queries))
+		semaphore := make(chan struct{}, maxBatchConcurrency)
+		done := make(chan struct{})
+		for i, query := range queries {
+			go func(i int, query BatchQuery) {
+				semaphore <- struct{}{}
+				results[i] = runBatchQuery(router, r, query)
+				<-semaphore
+				done <- struct{}{}
+			}(i, query)
+		}
+		for range queries {
+			<-done
+		}
+
+		res, err := converters.ConvertToJson(results)
+		writeJSON(w, res, err)
+	}
+}
+
+func runBatchQuery(router *mux.Router, parent *http.Request, query BatchQuery) BatchResult {
+	result := BatchResult{Path: query.Path}
+	target, err := url.Parse(query.Path)
+	if err != nil || !strings.HasPrefix(query.Path, "/") {
+		result.Status = http.StatusBadRequest
+		result.Error = "400 Bad Request: path must be an API route starting with /"
+		return result
+	}
+	if strings.TrimSuffix(target.Path, "/") == "/batch" {
+		result.Status = http.StatusBadRequest
+		result.Error = "400 Bad Request: batches cannot be nested"
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(parent.Context(), http.MethodGet, query.Path, nil)
+	if err != nil {
+		result.Status = http.StatusBadRequest
+		result.Error = "400 Bad Request: " + err.Error()
+		return result
+	}
+	var match mux.RouteMatch
+	if !router.Match(req, &match) {
+		result.Status = http.StatusNotFound
+		result.Error = fmt.Sprint(http.StatusNotFound) + " " + http.StatusText(http.StatusNotFound) + ": no route matches the path"
+		return result
+	}
+	response := newBatchResponse()
+	router.ServeHTTP(response, req)
+
+	result.Status = response.status
+	body := response.body.Bytes()
+	if response.status == http.StatusOK {
+		// Empty result sets come back with an empty body
+		if json.Valid(body) {
+			result.Result = body
+		}
+		var fields struct{ PeriodStart *string }
+		if json.Unmarshal(body, &fields) != nil || fields.PeriodStart == nil {
+			result.PeriodStart = response.header.Get("X-Period-Start")
+			result.PeriodEnd = response.header.Get("X-Period-End")
+		}
+		return result
+	}
+
+	// Errors of the handlers are JSON documents with a single Error field, other bodies are not passed on and the
+	// error is the status of the response
+	var errorResponse converters.Error
+	if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Error != "" {
+		result.Error = errorResponse.Error
+	} else {
+		result.Error = fmt.Sprint(response.status) + " " + http.StatusText(response.status)
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/converters"
+)
+
+// Routes served without calling the wikipedia API, so the batch can be tested on its own
+func newBatchTestRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/article/{article}/monthly/{year}/{month}", func(w http.ResponseWriter, r *http.Request) {
+		p, err := resolvePeriod(w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		res, err := converters.ConvertPageviewsToJson(485684, p)
+		writeJSON(w, res, err)
+	})
+	router.HandleFunc("/articles/top/monthly/{year}/{month}", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, errors.New("400 Bad Request: Given year/month/day is invalid date"))
+	})
+	router.HandleFunc("/articles/top/weekly/{year}/{week}", func(w http.ResponseWriter, r *http.Request) {
+		_, err := resolvePeriod(w, r)
+		writeJSON(w, []byte(`[{"Article":"ChatGPT","Views":1329459,"Rank":1}]`), err)
+	})
+	router.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something went wrong", http.StatusInternalServerError)
+	})
+	router.HandleFunc("/batch", BatchHandler(router)).Methods(http.MethodPost)
+	return router
+}
+
+func TestPOSTBatch(t *testing.T) {
+	t.Run("returns the result of every sub-query without failing the batch", func(t *testing.T) {
+		body := `[{"Path": "/article/Albert_Einstein/monthly/2023/04"}, {"Path": "/articles/top/monthly/2023/13"}, {"Path": "/unknown"}, {"Path": "/batch"}, ` +
+			`{"Path": "/batch/?n=1"}, {"Path": "/batches"}, {"Path": "/broken"}, {"Path": "/articles/top/weekly/2023/03?weekScheme=us"}]`
+		req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		newBatchTestRouter().ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusOK)
+		expected := `[{"Path":"/article/Albert_Einstein/monthly/2023/04","Status":200,"Result":{"Pageviews":"485684","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}},` +
+			`{"Path":"/articles/top/monthly/2023/13","Status":400,"Error":"400 Bad Request: Given year/month/day is invalid date"},` +
+			`{"Path":"/unknown","Status":404,"Error":"404 Not Found: no route matches the path"},` +
+			`{"Path":"/batch","Status":400,"Error":"400 Bad Request: batches cannot be nested"},` +
+			`{"Path":"/batch/?n=1","Status":400,"Error":"400 Bad Request: batches cannot be nested"},` +
+			`{"Path":"/batches","Status":404,"Error":"404 Not Found: no route matches the path"},` +
+			`{"Path":"/broken","Status":500,"Error":"500 Internal Server Error"},` +
+			`{"Path":"/articles/top/weekly/2023/03?weekScheme=us","Status":200,"PeriodStart":"2023-01-15","PeriodEnd":"2023-01-21",` +
+			`"Result":[{"Article":"ChatGPT","Views":1329459,"Rank":1}]}]`
+		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
+	})
+
+	t.Run("returns 400 when the body is not an array of sub-queries", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{"Path": "/articles/top/monthly/2023/03"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		newBatchTestRouter().ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+	})
+}
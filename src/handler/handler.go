@@ -3,12 +3,30 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/anomaly"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/articles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/compare"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/converters"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/crosslang"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/edits"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/forecast"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/groups"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/mediarequests"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/pageviews"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/redirects"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/titles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/trending"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
 )
 
 func parseStatusCode(input string) int {
@@ -33,26 +51,15 @@ func convertError(input string) ([]byte, error) {
 	return res, nil
 }
 
-func TopArticlesWeeklyHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	res, err := articles.GetTopArticlesByWeek(vars["year"], vars["week"])
+func TopFilesMonthlyHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		statusCode, conversionErr := strconv.Atoi(err.Error()[:3])
-		if conversionErr != nil {
-			fmt.Println("ERROR: ", err)
-			// if you cannot parse the status returned from the API, return 500
-			statusCode = http.StatusInternalServerError
-		}
-		// Convert returned error to JSON
-		res, err := convertError(err.Error())
-		if err != nil {
-			fmt.Println("ERROR: ", err)
-			w.WriteHeader(http.StatusInternalServerError)
-		} else {
-			w.WriteHeader(statusCode)
-		}
-		w.Write(res)
+		writeError(w, err)
+		return
+	}
+	res, err := mediarequests.GetTopFilesByMonth(p)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -60,125 +67,870 @@ func TopArticlesWeeklyHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(res))
 }
 
-func TopArticlesMonthlyHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	res, err := articles.GetTopArticlesByMonth(vars["year"], vars["month"])
+func RequestsPerFileHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		statusCode, conversionErr := strconv.Atoi(err.Error()[:3])
-		if conversionErr != nil {
-			fmt.Println("ERROR: ", err)
-			// if you cannot parse the status returned from the API, return 500
-			statusCode = http.StatusInternalServerError
+		writeError(w, err)
+		return
+	}
+	file, err := filePath(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	requests, err := mediarequests.GetRequests(file, p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Convert media requests result to JSON
+	res, err := converters.ConvertMediaRequestsToJson(requests, p)
+	writeJSON(w, res, err)
+}
+
+// Returns the upload path of the file of the route
+// The router keeps the path encoded so it is decoded once here, an encoded % (%25) is a % of the file name
+func filePath(r *http.Request) (string, error) {
+	file, err := url.PathUnescape(mux.Vars(r)["file"])
+	if err != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return "", fmt.Errorf(status400+": file must be a URL-encoded upload path: %s", err.Error())
+	}
+	return file, nil
+}
+
+func CompareHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := dateRange(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	granularity := query.Get("granularity")
+	if granularity == "" {
+		granularity = pageviews.Daily
+	}
+
+	mode, err := redirects.ParseMode(query.Get("redirects"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	articles, err := normalizeTitles(splitList(query.Get("articles")))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	comparison, err := compare.Compare(articles, pageviews.Query{Start: startDate, End: endDate, Granularity: granularity}, mode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(comparison)
+	writeJSON(w, res, err)
+}
+
+// Adds up the views of the editions of the editions query parameter (project:title pairs), or of the editions
+// of the concept query parameter in the interlanguage mapping
+func CrossLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := dateRange(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	granularity := query.Get("granularity")
+	if granularity == "" {
+		granularity = pageviews.Daily
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var editions []crosslang.Edition
+	if concept := query.Get("concept"); concept != "" {
+		editions, err = crosslang.Editions(concept)
+		if err != nil {
+			writeError(w, err)
+			return
 		}
-		// Convert returned error to JSON
-		res, err := convertError(err.Error())
+	}
+	for _, entry := range splitList(query.Get("editions")) {
+		edition, err := crosslang.ParseEdition(entry)
 		if err != nil {
-			fmt.Println("ERROR: ", err)
-			w.WriteHeader(http.StatusInternalServerError)
-		} else {
-			w.WriteHeader(statusCode)
+			writeError(w, err)
+			return
 		}
-		w.Write(res)
+		editions = append(editions, edition)
+	}
+
+	aggregate, err := crosslang.GetAggregate(editions, pageviews.Query{Start: startDate, End: endDate, Granularity: granularity})
+	if err != nil {
+		writeError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(res))
+	res, err := converters.ConvertToJson(aggregate)
+	writeJSON(w, res, err)
+}
+
+// The dates of the range query parameters, either a range expression like last-30-days or the start and end dates
+func dateRange(query url.Values) (time.Time, time.Time, error) {
+	if expression := query.Get("range"); expression != "" {
+		p, err := period.FromExpression(expression, time.Now())
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return p.Start, p.End, nil
+	}
+	startDate, err := utilities.ParseDate(query.Get("start"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endDate, err := utilities.ParseDate(query.Get("end"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startDate, endDate, nil
+}
+
+func PeaksHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := dateRange(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	granularity := query.Get("granularity")
+	if granularity == "" {
+		granularity = pageviews.Daily
+	}
+	n, err := intParam(r, "n", 10)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	scheme, err := utilities.ParseWeekScheme(query.Get("weekScheme"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	peaks, err := pageviews.GetPeaks(titles, pageviews.Query{Start: startDate, End: endDate, Granularity: granularity}, n, scheme)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(peaks)
+	writeJSON(w, res, err)
+}
+
+func StatsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := dateRange(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	agent, err := pageviews.ParseAgent(query.Get("agent"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	percentiles, err := floatListParam(r, "percentiles", pageviews.DefaultPercentiles)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	summary, err := pageviews.GetSummary(titles, pageviews.Query{Start: startDate, End: endDate, Agent: agent}, percentiles)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(summary)
+	writeJSON(w, res, err)
 }
 
-func ViewsPerArticleWeeklyHandler(w http.ResponseWriter, r *http.Request) {
+func AnomaliesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := dateRange(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	granularity := query.Get("granularity")
+	if granularity == "" {
+		granularity = pageviews.Daily
+	}
+	options := anomaly.DefaultOptions()
+	if method := query.Get("method"); method != "" {
+		options.Method = method
+	}
+	options.Window, err = intParam(r, "window", options.Window)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	options.Threshold, err = floatParam(r, "threshold", options.Threshold)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	anomalies, err := anomaly.GetAnomalies(titles, pageviews.Query{Start: startDate, End: endDate, Granularity: granularity}, options)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(anomalies)
+	writeJSON(w, res, err)
+}
+
+func ForecastHandler(w http.ResponseWriter, r *http.Request) {
+	horizon, err := intParam(r, "horizon", forecast.DefaultHorizon)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	history, err := intParam(r, "history", forecast.DefaultHistory)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := forecast.GetForecast(titles, horizon, history, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(result)
+	writeJSON(w, res, err)
+}
+
+// Redirects are only resolved, the top lists rank the views of the article and its redirects separately
+func RankHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	startDate, endDate, err := dateRange(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	history, err := articles.GetRankHistory(titles[0], startDate, endDate)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(history)
+	writeJSON(w, res, err)
+}
+
+func TrendingArticlesHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	pageviews, err := pageviews.GetPageviewsByWeek(vars["article"], vars["year"], vars["week"])
+	date, err := utilities.ParseDate(vars["date"])
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		// Parse returned error to see if there is an HTTP status there
-		statusCode := parseStatusCode(err.Error()[:3])
-		w.WriteHeader(statusCode)
+		writeError(w, err)
+		return
+	}
+	window, err := intParam(r, "window", 1)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	baseline, err := intParam(r, "baseline", 7)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	risers, err := trending.GetTrendingArticles(date, window, baseline, articleFilter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(risers)
+	writeJSON(w, res, err)
+}
+
+// Builds the filter of the top lists from the namespaces, exclude and denylist query parameters
+func parseFilter(r *http.Request) (filter.Filter, error) {
+	query := r.URL.Query()
+	articleFilter := filter.Filter{
+		Namespaces: splitList(query.Get("namespaces")),
+		Exclude:    splitList(query.Get("exclude")),
+	}
+	var err error
+	articleFilter.Denylist, err = boolParam(r, "denylist")
+	if err != nil {
+		return filter.Filter{}, err
+	}
+	return articleFilter, articleFilter.Validate()
+}
+
+// Returns the value of a true or false query parameter, false if it is not set
+func boolParam(r *http.Request, name string) (bool, error) {
+	input := r.URL.Query().Get(name)
+	if input == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(input)
+	if err != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return false, fmt.Errorf(status400+": %s must be true or false", name)
+	}
+	return value, nil
+}
+
+// Returns the value of a numeric query parameter, or the default value if it is not set
+func intParam(r *http.Request, name string, defaultValue int) (int, error) {
+	input := r.URL.Query().Get(name)
+	if input == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return 0, fmt.Errorf(status400+": %s must be a number", name)
+	}
+	return value, nil
+}
+
+// Returns the value of a decimal query parameter, or the default value if it is not set
+func floatParam(r *http.Request, name string, defaultValue float64) (float64, error) {
+	input := r.URL.Query().Get(name)
+	if input == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return 0, fmt.Errorf(status400+": %s must be a number", name)
+	}
+	return value, nil
+}
 
-		// Convert returned error to JSON
-		res, err := convertError(err.Error())
+// Parses a comma separated query parameter of numbers
+func floatListParam(r *http.Request, name string, defaultValue []float64) ([]float64, error) {
+	entries := splitList(r.URL.Query().Get(name))
+	if len(entries) == 0 {
+		return defaultValue, nil
+	}
+	values := make([]float64, len(entries))
+	for i, entry := range entries {
+		value, err := strconv.ParseFloat(entry, 64)
 		if err != nil {
-			fmt.Println("ERROR: ", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			return nil, fmt.Errorf(status400+": %s must be a list of numbers", name)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Parses a comma separated query parameter of whole numbers
+func intListParam(r *http.Request, name string, defaultValue []int) ([]int, error) {
+	entries := splitList(r.URL.Query().Get(name))
+	if len(entries) == 0 {
+		return defaultValue, nil
+	}
+	values := make([]int, len(entries))
+	for i, entry := range entries {
+		value, err := strconv.Atoi(entry)
+		if err != nil {
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			return nil, fmt.Errorf(status400+": %s must be a list of numbers", name)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Splits a comma separated query parameter, ignoring empty entries
+func splitList(input string) []string {
+	var list []string
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			list = append(list, entry)
 		}
-		w.Write(res)
-		return
+	}
+	return list
+}
+
+// Writes the error as JSON using the HTTP status found at the beginning of the error message
+func writeError(w http.ResponseWriter, err error) {
+	fmt.Println("ERROR: ", err)
+	statusCode := http.StatusInternalServerError
+	if len(err.Error()) >= 3 {
+		statusCode = parseStatusCode(err.Error()[:3])
 	}
 
-	// Convert pageviews result to JSON
-	res, err := converters.ConvertPageviewsToJson(pageviews)
+	res, err := convertError(err.Error())
 	if err != nil {
 		fmt.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		res, _ := convertError(err.Error())
-		w.Write([]byte(res))
+		statusCode = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(res)
+}
+
+// Writes a successful JSON response, or the conversion error if the result could not be converted
+func writeJSON(w http.ResponseWriter, res []byte, err error) {
+	if err != nil {
+		writeError(w, err)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write(res)
 }
 
-func ViewsPerArticleMonthlyHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	pageviews, err := pageviews.GetPageviewsByMonth(vars["article"], vars["year"], vars["month"])
+// Returns the titles whose views are counted for the article, depending on the redirects query parameter
+func articleTitles(r *http.Request, article string) ([]string, error) {
+	mode, err := redirects.ParseMode(r.URL.Query().Get("redirects"))
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		// Parse returned error to see if there is an HTTP status there
-		statusCode := parseStatusCode(err.Error()[:3])
-		w.WriteHeader(statusCode)
+		return nil, err
+	}
+	article, err = titles.Normalize(filter.DefaultProject, article)
+	if err != nil {
+		return nil, err
+	}
+	return redirects.Titles(article, mode)
+}
 
-		// Convert returned error to JSON
-		res, err := convertError(err.Error())
+// Normalizes every title of the list
+func normalizeTitles(input []string) ([]string, error) {
+	normalized := make([]string, len(input))
+	for i, title := range input {
+		var err error
+		normalized[i], err = titles.Normalize(filter.DefaultProject, title)
 		if err != nil {
-			fmt.Println("ERROR: ", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			return nil, err
 		}
-		w.Write(res)
+	}
+	return normalized, nil
+}
+
+func ArticleActivityHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	article, err := titles.Normalize(filter.DefaultProject, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
 		return
 	}
+	views, err := pageviews.GetPageviews([]string{article}, p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	editCount, err := edits.GetEditsPerPage(article, p.Start, p.End)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertArticleActivityToJson(views, editCount, p)
+	writeJSON(w, res, err)
+}
 
-	// Convert pageviews result to JSON
-	res, err := converters.ConvertPageviewsToJson(pageviews)
+func ProjectActivityMonthlyHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		res, _ := convertError(err.Error())
-		w.Write([]byte(res))
+		writeError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(res)
+	editors, err := edits.GetEditors(p.Start, p.End)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	newPages, err := edits.GetNewPages(p.Start, p.End)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// A single month is requested so there is at most one result
+	editorCount := 0
+	if len(editors) > 0 {
+		editorCount = editors[0].Editors
+	}
+	res, err := converters.ConvertProjectActivityToJson(editorCount, newPages, p)
+	writeJSON(w, res, err)
 }
 
-func TopViewsPerArticleMonthlyHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	timestamp, pageviews, err := pageviews.GetDayWithMostPageviews(vars["article"], vars["year"], vars["month"])
+// Builds the period of the route from its variables and the weekScheme query parameter
+// The first and last days of the period are echoed in the X-Period-Start and X-Period-End headers of the response
+func resolvePeriod(w http.ResponseWriter, r *http.Request) (period.Period, error) {
+	scheme, err := utilities.ParseWeekScheme(r.URL.Query().Get("weekScheme"))
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		// Parse returned error to see if there is an HTTP status there
-		statusCode := parseStatusCode(err.Error()[:3])
-		w.WriteHeader(statusCode)
+		return period.Period{}, err
+	}
+	p, err := period.FromVars(mux.Vars(r), scheme)
+	if err != nil {
+		return period.Period{}, err
+	}
+	w.Header().Set("X-Period-Start", p.Start.Format("2006-01-02"))
+	w.Header().Set("X-Period-End", p.End.Format("2006-01-02"))
+	return p, nil
+}
+
+// Builds the period like resolvePeriod, in the time zone of the tz query parameter
+// Only the project views support time zones, the other endpoints are read from daily views or lists of UTC days
+func resolveLocalPeriod(w http.ResponseWriter, r *http.Request) (period.Period, error) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		return period.Period{}, err
+	}
+	p.Location, err = period.LoadLocation(r.URL.Query().Get("tz"))
+	if err != nil {
+		return period.Period{}, err
+	}
+	return p, nil
+}
+
+// Rejects the tz query parameter of the routes of articles, time zones are added up from hourly views and the
+// wikipedia API only has them for the whole project. UTC is accepted since it is the time zone of the daily views.
+func rejectTimeZone(r *http.Request) error {
+	location, err := period.LoadLocation(r.URL.Query().Get("tz"))
+	if err != nil {
+		return err
+	}
+	if location != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return fmt.Errorf(status400 + ": tz is only supported by the project views, the wikipedia API has no hourly views per article")
+	}
+	return nil
+}
+
+func ViewsPerArticleHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePageviews(w, r, p, func(p period.Period) (int, error) {
+		return pageviews.GetPageviews(titles, p)
+	})
+}
 
-		// Convert returned error to JSON
-		res, err := convertError(err.Error())
+func ProjectViewsHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolveLocalPeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	agent, err := pageviews.ParseAgent(r.URL.Query().Get("agent"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePageviews(w, r, p, func(p period.Period) (int, error) {
+		return pageviews.GetProjectPageviews(p, agent)
+	})
+}
+
+// Writes the views of the period, along with the change since the period of the compare query parameter
+// (previous or year-ago) if it is set. Both periods are fetched concurrently.
+func writePageviews(w http.ResponseWriter, r *http.Request, p period.Period, getViews func(period.Period) (int, error)) {
+	option := r.URL.Query().Get("compare")
+	if option == "" {
+		views, err := getViews(p)
 		if err != nil {
-			fmt.Println("ERROR: ", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
+			return
 		}
-		w.Write(res)
+		res, err := converters.ConvertPageviewsToJson(views, p)
+		writeJSON(w, res, err)
 		return
 	}
 
-	// Convert pageviews result to JSON
-	res, err := converters.ConvertTopDayPageviewsToJson(timestamp, pageviews)
+	earlier, err := p.Comparison(option)
 	if err != nil {
-		fmt.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		res, _ := convertError(err.Error())
-		w.Write([]byte(res))
+		writeError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(res)
+	var earlierViews int
+	var earlierErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		earlierViews, earlierErr = getViews(earlier)
+	}()
+	views, err := getViews(p)
+	wg.Wait()
+	if err == nil {
+		err = earlierErr
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertComparedPageviewsToJson(views, pageviews.NewChange(views, earlier, earlierViews), p)
+	writeJSON(w, res, err)
+}
+
+// Returns the day with the most views, or the month with by=month
+func TopViewsPerArticleHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = rejectTimeZone(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	granularity := pageviews.Daily
+	switch r.URL.Query().Get("by") {
+	case "", "day":
+	case "month":
+		granularity = pageviews.Monthly
+	default:
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		writeError(w, fmt.Errorf(status400+": by must be day or month"))
+		return
+	}
+	titles, err := articleTitles(r, mux.Vars(r)["article"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	timestamp, views, err := pageviews.GetTopOfPeriod(titles, p, granularity)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertTopDayPageviewsToJson(timestamp, views, p)
+	writeJSON(w, res, err)
+}
+
+func TopArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	method, err := ranking.ParseMethod(r.URL.Query().Get("ties"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	share, err := boolParam(r, "share")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	getTopArticles := articles.GetTopArticles
+	if share {
+		getTopArticles = articles.GetTopArticlesWithShares
+	}
+	res, err := getTopArticles(p, articleFilter, method)
+	writeJSON(w, []byte(res), err)
+}
+
+// Compares the top articles of the period with the previous period, or the period of the compare parameter
+func ChurnHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	option := r.URL.Query().Get("compare")
+	if option == "" {
+		option = period.Previous
+	}
+	earlier, err := p.Comparison(option)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	n, err := intParam(r, "n", articles.DefaultChurnSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	churn, err := articles.GetChurn(p, earlier, articleFilter, n)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(churn)
+	writeJSON(w, res, err)
+}
+
+func DistributionHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	head, err := intParam(r, "head", articles.DefaultHeadSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ranks, err := intListParam(r, "ranks", articles.DefaultRanks)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	bins, err := intParam(r, "bins", articles.DefaultBins)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	distribution, err := articles.GetDistribution(p, articleFilter, head, ranks, bins)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(distribution)
+	writeJSON(w, res, err)
+}
+
+func GroupsTopHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	method, err := ranking.ParseMethod(r.URL.Query().Get("ties"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	groupViews, err := groups.GetGroupViews(p, articleFilter, method)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(groupViews)
+	writeJSON(w, res, err)
+}
+
+func GroupTopArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := resolvePeriod(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	articleFilter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	method, err := ranking.ParseMethod(r.URL.Query().Get("ties"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	n, err := intParam(r, "n", groups.DefaultSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	groupArticles, err := groups.GetTopArticlesOfGroup(mux.Vars(r)["group"], p, articleFilter, n, method)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := converters.ConvertToJson(groupArticles)
+	writeJSON(w, res, err)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -26,7 +27,7 @@ func TestGETTopArticlesWeekly(t *testing.T) {
 
 		// Create a router through which we can pass the request vars.
 		router := mux.NewRouter()
-		router.HandleFunc("/articles/top/weekly/{year}/{week}", TopArticlesWeeklyHandler)
+		router.HandleFunc("/articles/top/weekly/{year}/{week}", TopArticlesHandler)
 		router.ServeHTTP(rr, req)
 
 		// Check the status code is what we expect.
@@ -55,7 +56,7 @@ func TestGETTopArticlesMonthly(t *testing.T) {
 
 		// Create a router through which we can pass the request vars.
 		router := mux.NewRouter()
-		router.HandleFunc("/articles/top/monthly/{year}/{month}", TopArticlesMonthlyHandler)
+		router.HandleFunc("/articles/top/monthly/{year}/{month}", TopArticlesHandler)
 		router.ServeHTTP(rr, req)
 
 		// Check the status code is what we expect.
@@ -84,14 +85,14 @@ func TestGETViewsPerArticleWeekly(t *testing.T) {
 
 		// Create a router through which we can pass the request vars.
 		router := mux.NewRouter()
-		router.HandleFunc("/article/{article}/weekly/{year}/{week}", ViewsPerArticleWeeklyHandler)
+		router.HandleFunc("/article/{article}/weekly/{year}/{week}", ViewsPerArticleHandler)
 		router.ServeHTTP(rr, req)
 
 		// Check the status code is what we expect.
 		assertResponseField(t, "wrong status code", rr.Code, http.StatusOK)
 
 		// Check the response body is what we expect.
-		expected := `{"Pageviews":"157023"}`
+		expected := `{"Pageviews":"157023","PeriodStart":"2023-01-16","PeriodEnd":"2023-01-22"}`
 		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
 	})
 }
@@ -113,14 +114,14 @@ func TestGETViewsPerArticleMonthly(t *testing.T) {
 
 		// Create a router through which we can pass the request vars.
 		router := mux.NewRouter()
-		router.HandleFunc("/article/{article}/monthly/{year}/{month}", ViewsPerArticleMonthlyHandler)
+		router.HandleFunc("/article/{article}/monthly/{year}/{month}", ViewsPerArticleHandler)
 		router.ServeHTTP(rr, req)
 
 		// Check the status code is what we expect.
 		assertResponseField(t, "wrong status code", rr.Code, http.StatusOK)
 
 		// Check the response body is what we expect.
-		expected := `{"Pageviews":"485684"}`
+		expected := `{"Pageviews":"485684","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`
 		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
 	})
 }
@@ -142,18 +143,351 @@ func TestGETDayWithMostPageviews(t *testing.T) {
 
 		// Create a router through which we can pass the request vars.
 		router := mux.NewRouter()
-		router.HandleFunc("/article/{article}/top/monthly/{year}/{month}", TopViewsPerArticleMonthlyHandler)
+		router.HandleFunc("/article/{article}/top/monthly/{year}/{month}", TopViewsPerArticleHandler)
 		router.ServeHTTP(rr, req)
 
 		// Check the status code is what we expect.
 		assertResponseField(t, "wrong status code", rr.Code, http.StatusOK)
 
 		// Check the response body is what we expect.
-		expected := `{"Pageviews":"30724","Timestamp":"2023042200"}`
+		expected := `{"Pageviews":"30724","Timestamp":"2023042200","PeriodStart":"2023-04-01","PeriodEnd":"2023-04-30"}`
 		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
 	})
 }
 
+func TestGETCompareInvalidInput(t *testing.T) {
+	t.Run("returns 400 when too many articles are compared", func(t *testing.T) {
+		// Build the request URL with one article more than allowed.
+		articles := make([]string, 21)
+		for i := range articles {
+			articles[i] = fmt.Sprintf("Article_%d", i)
+		}
+		path := fmt.Sprintf("/compare?articles=%s&start=20230301&end=20230331", strings.Join(articles, ","))
+
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/compare", CompareHandler)
+		router.ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+		expected := `{"Error":"400 Bad Request: between 1 and 20 articles can be compared"}`
+		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
+	})
+
+	t.Run("returns 400 when a date is not in YYYYMMDD format", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/compare?articles=ChatGPT&start=2023-03-01&end=20230331", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/compare", CompareHandler)
+		router.ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+		expected := `{"Error":"400 Bad Request: \"2023-03-01\" is not a valid date in YYYYMMDD format"}`
+		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
+	})
+}
+
+func TestGETViewsPerArticleWeeklyInvalidWeekScheme(t *testing.T) {
+	t.Run("returns 400 for an unknown week scheme", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/article/Albert_Einstein/weekly/2023/03?weekScheme=friday", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/article/{article}/weekly/{year}/{week}", ViewsPerArticleHandler)
+		router.ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+		expected := `{"Error":"400 Bad Request: weekScheme must be one of iso, us, saturday, broadcast"}`
+		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
+	})
+
+	t.Run("returns 400 for a week that does not exist in the scheme", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/article/Albert_Einstein/weekly/2023/53?weekScheme=iso", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/article/{article}/weekly/{year}/{week}", ViewsPerArticleHandler)
+		router.ServeHTTP(rr, req)
+
+		assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+		expected := `{"Error":"400 Bad Request: input week cannot be greater than 52"}`
+		assertResponseField(t, "unexpected body", rr.Body.String(), expected)
+	})
+}
+
+func TestGETPeriodInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{
+			name:         "quarter out of range",
+			path:         "/article/Albert_Einstein/quarterly/2023/5",
+			expectedBody: `{"Error":"400 Bad Request: input quarter must be between 1 and 4"}`,
+		},
+		{
+			name:         "relative window longer than allowed",
+			path:         "/article/Albert_Einstein/last-400-days",
+			expectedBody: `{"Error":"400 Bad Request: last-N-days must be between 1 and 366 days"}`,
+		},
+		{
+			name:         "unknown compare range",
+			path:         "/compare?articles=ChatGPT&range=last-week",
+			expectedBody: `{"Error":"400 Bad Request: \"last-week\" must be one of yesterday, last-N-days, month-to-date, year-to-date"}`,
+		},
+		{
+			name:         "unknown time zone",
+			path:         "/article/Albert_Einstein/quarterly/2023/1?tz=Pacific/Nowhere",
+			expectedBody: `{"Error":"400 Bad Request: unknown time zone \"Pacific/Nowhere\""}`,
+		},
+		{
+			name:         "time zone of an article",
+			path:         "/article/Albert_Einstein/quarterly/2023/1?tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of the top day of an article",
+			path:         "/article/Albert_Einstein/top/yearly/2023?tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of compared articles",
+			path:         "/compare?articles=ChatGPT&range=last-30-days&tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of the editions of an article",
+			path:         "/crosslang?editions=de.wikipedia:Albert_Einstein&range=last-30-days&tz=Europe/Berlin",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of the peaks of an article",
+			path:         "/article/Albert_Einstein/peaks?range=last-30-days&tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of the stats of an article",
+			path:         "/article/Albert_Einstein/stats?range=last-30-days&tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "time zone of the anomalies of an article",
+			path:         "/article/Albert_Einstein/anomalies?range=last-30-days&tz=America/Los_Angeles",
+			expectedBody: `{"Error":"400 Bad Request: tz is only supported by the project views, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "too many peaks",
+			path:         "/article/Albert_Einstein/peaks?start=20230101&end=20230131&n=101",
+			expectedBody: `{"Error":"400 Bad Request: n must be between 1 and 100"}`,
+		},
+		{
+			name:         "unknown peaks granularity",
+			path:         "/article/Albert_Einstein/peaks?range=last-30-days&granularity=yearly",
+			expectedBody: `{"Error":"400 Bad Request: granularity must be daily, weekly or monthly"}`,
+		},
+		{
+			name:         "hourly peaks",
+			path:         "/article/Albert_Einstein/peaks?range=last-30-days&granularity=hourly",
+			expectedBody: `{"Error":"400 Bad Request: granularity must be daily, weekly or monthly"}`,
+		},
+		{
+			name:         "unknown stats agent",
+			path:         "/article/Albert_Einstein/stats?range=last-30-days&agent=bots",
+			expectedBody: `{"Error":"400 Bad Request: agent must be one of all-agents, user, spider, automated"}`,
+		},
+		{
+			name:         "stats percentile out of range",
+			path:         "/article/Albert_Einstein/stats?range=last-30-days&percentiles=50,150",
+			expectedBody: `{"Error":"400 Bad Request: percentiles must be between 0 and 100"}`,
+		},
+		{
+			name:         "unknown anomaly method",
+			path:         "/article/Albert_Einstein/anomalies?range=last-30-days&method=zscore",
+			expectedBody: `{"Error":"400 Bad Request: method must be rolling or seasonal"}`,
+		},
+		{
+			name:         "anomaly threshold is not a number",
+			path:         "/article/Albert_Einstein/anomalies?range=last-30-days&threshold=high",
+			expectedBody: `{"Error":"400 Bad Request: threshold must be a number"}`,
+		},
+		{
+			name:         "monthly anomalies",
+			path:         "/article/Albert_Einstein/anomalies?range=last-30-days&granularity=monthly",
+			expectedBody: `{"Error":"400 Bad Request: granularity must be daily, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "hourly anomalies",
+			path:         "/article/Albert_Einstein/anomalies?range=last-30-days&granularity=hourly",
+			expectedBody: `{"Error":"400 Bad Request: granularity must be daily, the wikipedia API has no hourly views per article"}`,
+		},
+		{
+			name:         "forecast horizon too long",
+			path:         "/article/Albert_Einstein/forecast?horizon=400",
+			expectedBody: `{"Error":"400 Bad Request: horizon must be between 1 and 365 days"}`,
+		},
+		{
+			name:         "unknown comparison period",
+			path:         "/article/Albert_Einstein/quarterly/2023/1?compare=last-year",
+			expectedBody: `{"Error":"400 Bad Request: compare must be previous or year-ago"}`,
+		},
+		{
+			name:         "unknown project views agent",
+			path:         "/project/views/yearly/2023?agent=bots",
+			expectedBody: `{"Error":"400 Bad Request: agent must be one of all-agents, user, spider, automated"}`,
+		},
+		{
+			name:         "rank history start after end",
+			path:         "/article/Albert_Einstein/rank-history?start=20230301&end=20230201",
+			expectedBody: `{"Error":"400 Bad Request: start date cannot be after end date"}`,
+		},
+		{
+			name:         "unknown churn comparison",
+			path:         "/articles/churn/monthly/2023/03?compare=last-year",
+			expectedBody: `{"Error":"400 Bad Request: compare must be previous or year-ago"}`,
+		},
+		{
+			name:         "churn list too long",
+			path:         "/articles/churn/monthly/2023/03?n=500",
+			expectedBody: `{"Error":"400 Bad Request: n must be between 1 and 100"}`,
+		},
+		{
+			name:         "unknown tie ranking",
+			path:         "/articles/top/yearly/2023?ties=average",
+			expectedBody: `{"Error":"400 Bad Request: ties must be ordinal, competition or dense"}`,
+		},
+		{
+			name:         "group top list too long",
+			path:         "/groups/Sports/top/monthly/2023/03?n=500",
+			expectedBody: `{"Error":"400 Bad Request: n must be between 1 and 100"}`,
+		},
+		{
+			name:         "edition without a project",
+			path:         "/crosslang?editions=Albert_Einstein&range=last-30-days",
+			expectedBody: `{"Error":"400 Bad Request: \"Albert_Einstein\" must be a project and a title, e.g. de.wikipedia:Albert_Einstein"}`,
+		},
+		{
+			name:         "no editions",
+			path:         "/crosslang?range=last-30-days",
+			expectedBody: `{"Error":"400 Bad Request: between 1 and 50 editions can be added up"}`,
+		},
+		{
+			name:         "share is not a boolean",
+			path:         "/articles/top/yearly/2023?share=yes",
+			expectedBody: `{"Error":"400 Bad Request: share must be true or false"}`,
+		},
+		{
+			name:         "ranks past the end of the monthly list",
+			path:         "/articles/distribution/monthly/2023/03?ranks=1,5000",
+			expectedBody: `{"Error":"400 Bad Request: ranks must be between 1 and 1000"}`,
+		},
+		{
+			name:         "ranks are not numbers",
+			path:         "/articles/distribution/monthly/2023/03?ranks=first",
+			expectedBody: `{"Error":"400 Bad Request: ranks must be a list of numbers"}`,
+		},
+		{
+			name:         "too many histogram bins",
+			path:         "/articles/distribution/monthly/2023/03?bins=100",
+			expectedBody: `{"Error":"400 Bad Request: bins must be between 1 and 50"}`,
+		},
+		{
+			name:         "unknown top granularity",
+			path:         "/article/Albert_Einstein/top/yearly/2023?by=week",
+			expectedBody: `{"Error":"400 Bad Request: by must be day or month"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/article/{article}/top/yearly/{year}", TopViewsPerArticleHandler)
+			router.HandleFunc("/article/{article}/quarterly/{year}/{quarter}", ViewsPerArticleHandler)
+			router.HandleFunc("/article/{article}/{relative:last-[0-9]+-days}", ViewsPerArticleHandler)
+			router.HandleFunc("/compare", CompareHandler)
+			router.HandleFunc("/crosslang", CrossLanguageHandler)
+			router.HandleFunc("/article/{article}/peaks", PeaksHandler)
+			router.HandleFunc("/article/{article}/stats", StatsHandler)
+			router.HandleFunc("/article/{article}/anomalies", AnomaliesHandler)
+			router.HandleFunc("/article/{article}/forecast", ForecastHandler)
+			router.HandleFunc("/project/views/yearly/{year}", ProjectViewsHandler)
+			router.HandleFunc("/article/{article}/rank-history", RankHistoryHandler)
+			router.HandleFunc("/articles/churn/monthly/{year}/{month}", ChurnHandler)
+			router.HandleFunc("/articles/top/yearly/{year}", TopArticlesHandler)
+			router.HandleFunc("/articles/distribution/monthly/{year}/{month}", DistributionHandler)
+			router.HandleFunc("/groups/{group}/top/monthly/{year}/{month}", GroupTopArticlesHandler)
+			router.ServeHTTP(rr, req)
+
+			assertResponseField(t, "wrong status code", rr.Code, http.StatusBadRequest)
+			assertResponseField(t, "unexpected body", rr.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	testCases := []struct {
+		name         string
+		path         string
+		expectedFile string
+	}{
+		{
+			name:         "file name with an encoded %",
+			path:         "/file/wikipedia/commons/5/5f/100%25_Pure.jpg/monthly/2023/04",
+			expectedFile: "wikipedia/commons/5/5f/100%_Pure.jpg",
+		},
+		{
+			name:         "file name with encoded unicode characters",
+			path:         "/file/wikipedia/commons/1/1e/%C3%86thelred_II.jpg/monthly/2023/04",
+			expectedFile: "wikipedia/commons/1/1e/Æthelred_II.jpg",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			// the same router options as main, the path is only decoded by filePath
+			router := mux.NewRouter().UseEncodedPath()
+			router.HandleFunc("/file/{file:.+}/monthly/{year}/{month}", func(w http.ResponseWriter, r *http.Request) {
+				got, err = filePath(r)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertResponseField(t, "unexpected file", got, tc.expectedFile)
+		})
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"file": "wikipedia/commons/5/5f/100%_Pure.jpg"})
+	_, err := filePath(req)
+	assertResponseField(t, "unexpected error", err.Error(), `400 Bad Request: file must be a URL-encoded upload path: invalid URL escape "%_P"`)
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" ChatGPT,,Google_Bard ,")
+	assertResponseField(t, "wrong length", len(got), 2)
+	assertResponseField(t, "wrong first entry", got[0], "ChatGPT")
+	assertResponseField(t, "wrong second entry", got[1], "Google_Bard")
+}
+
 // use interface{} for input so it can be either string or int
 func assertResponseField(t testing.TB, fieldAsserted string, got, want interface{}) {
 	t.Helper()
@@ -3,18 +3,106 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	// Embed the time zone database so the tz parameter works on images without one
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/crosslang"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/groups"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/handler"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/redirects"
 )
 
+// Any valid MediaWiki title, the handlers decode and normalize it
+const articlePattern = "{article:.+}"
+
+// Periods relative to the last day with complete data, see period.FromExpression
+const relativePattern = "{relative:yesterday|last-[0-9]+-days|month-to-date|year-to-date}"
+
 func main() {
-	r := mux.NewRouter()
-	r.HandleFunc("/articles/top/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.TopArticlesWeeklyHandler)
-	r.HandleFunc("/articles/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.TopArticlesMonthlyHandler)
-	r.HandleFunc("/article/{article:[0-9a-zA-Z_%,-.~()'!:@;*]+}/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.ViewsPerArticleWeeklyHandler)
-	r.HandleFunc("/article/{article:[0-9a-zA-Z_%,-.~()'!:@;*]+}/monthly/{year}/{month}", handler.ViewsPerArticleMonthlyHandler)
-	r.HandleFunc("/article/{article:[0-9a-zA-Z_%,-.~()'!:@;*]+}/top/monthly/{year}/{month}", handler.TopViewsPerArticleMonthlyHandler)
+	// Redirects are looked up with the MediaWiki API unless a file with the redirects is given for offline use
+	if path := os.Getenv("REDIRECTS_FILE"); path != "" {
+		source, err := redirects.NewFileSource(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		redirects.SetSource(source)
+	} else {
+		redirects.SetSource(redirects.NewAPISource())
+	}
+
+	// Articles are only grouped if a file with the groups of the articles is given
+	if path := os.Getenv("GROUPS_FILE"); path != "" {
+		source, err := groups.NewFileSource(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		groups.SetSource(source)
+	}
+
+	// Concepts can only be looked up by name if a file with the titles of every concept on every project is given
+	if path := os.Getenv("INTERLANGUAGE_FILE"); path != "" {
+		mapping, err := crosslang.NewFileMapping(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		crosslang.SetMapping(mapping)
+	}
+
+	// Keep the path encoded so titles with an encoded / (%2F) or ? (%3F) are matched as a single article
+	r := mux.NewRouter().UseEncodedPath()
+	r.HandleFunc("/articles/top/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.TopArticlesHandler)
+	r.HandleFunc("/articles/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.TopArticlesHandler)
+	r.HandleFunc("/articles/top/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.TopArticlesHandler)
+	r.HandleFunc("/articles/top/yearly/{year:[0-9]+}", handler.TopArticlesHandler)
+	r.HandleFunc("/articles/top/"+relativePattern, handler.TopArticlesHandler)
+	r.HandleFunc("/articles/churn/daily/{date:[0-9]{8}}", handler.ChurnHandler)
+	r.HandleFunc("/articles/churn/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.ChurnHandler)
+	r.HandleFunc("/articles/churn/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.ChurnHandler)
+	r.HandleFunc("/articles/churn/"+relativePattern, handler.ChurnHandler)
+	r.HandleFunc("/articles/distribution/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.DistributionHandler)
+	r.HandleFunc("/groups/top/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.GroupsTopHandler)
+	r.HandleFunc("/groups/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.GroupsTopHandler)
+	r.HandleFunc("/groups/top/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.GroupsTopHandler)
+	r.HandleFunc("/groups/top/yearly/{year:[0-9]+}", handler.GroupsTopHandler)
+	r.HandleFunc("/groups/top/"+relativePattern, handler.GroupsTopHandler)
+	r.HandleFunc("/groups/{group}/top/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.GroupTopArticlesHandler)
+	r.HandleFunc("/groups/{group}/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.GroupTopArticlesHandler)
+	r.HandleFunc("/groups/{group}/top/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.GroupTopArticlesHandler)
+	r.HandleFunc("/groups/{group}/top/yearly/{year:[0-9]+}", handler.GroupTopArticlesHandler)
+	r.HandleFunc("/groups/{group}/top/"+relativePattern, handler.GroupTopArticlesHandler)
+	// Titles can contain slashes, e.g. AC/DC, so the article routes with a longer suffix must be registered before
+	// the shorter ones, otherwise /article/AC/DC/top/monthly/2023/04 would match the article "AC/DC/top"
+	r.HandleFunc("/article/"+articlePattern+"/peaks", handler.PeaksHandler)
+	r.HandleFunc("/article/"+articlePattern+"/stats", handler.StatsHandler)
+	r.HandleFunc("/article/"+articlePattern+"/anomalies", handler.AnomaliesHandler)
+	r.HandleFunc("/article/"+articlePattern+"/forecast", handler.ForecastHandler)
+	r.HandleFunc("/article/"+articlePattern+"/rank-history", handler.RankHistoryHandler)
+	r.HandleFunc("/article/"+articlePattern+"/activity/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.ArticleActivityHandler)
+	r.HandleFunc("/article/"+articlePattern+"/activity/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.ArticleActivityHandler)
+	r.HandleFunc("/article/"+articlePattern+"/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.TopViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/top/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.TopViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/top/yearly/{year:[0-9]+}", handler.TopViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/top/"+relativePattern, handler.TopViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.ViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.ViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.ViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/yearly/{year:[0-9]+}", handler.ViewsPerArticleHandler)
+	r.HandleFunc("/article/"+articlePattern+"/"+relativePattern, handler.ViewsPerArticleHandler)
+	r.HandleFunc("/project/activity/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.ProjectActivityMonthlyHandler)
+	r.HandleFunc("/project/views/weekly/{year:[0-9]+}/{week:[0-9]+}", handler.ProjectViewsHandler)
+	r.HandleFunc("/project/views/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.ProjectViewsHandler)
+	r.HandleFunc("/project/views/quarterly/{year:[0-9]+}/{quarter:[0-9]+}", handler.ProjectViewsHandler)
+	r.HandleFunc("/project/views/yearly/{year:[0-9]+}", handler.ProjectViewsHandler)
+	r.HandleFunc("/project/views/"+relativePattern, handler.ProjectViewsHandler)
+	r.HandleFunc("/files/top/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.TopFilesMonthlyHandler)
+	// The upload path of the file contains slashes, e.g. /file/wikipedia/commons/a/a9/Example.jpg/monthly/2023/04
+	r.HandleFunc("/file/{file:.+}/monthly/{year:[0-9]+}/{month:[0-9]+}", handler.RequestsPerFileHandler)
+	r.HandleFunc("/articles/trending/{date:[0-9]{8}}", handler.TrendingArticlesHandler)
+	r.HandleFunc("/compare", handler.CompareHandler)
+	r.HandleFunc("/crosslang", handler.CrossLanguageHandler)
+	r.HandleFunc("/batch", handler.BatchHandler(r)).Methods(http.MethodPost)
 	http.Handle("/", r)
 
 	log.Println("Listening on localhost:8080")
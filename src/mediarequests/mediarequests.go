@@ -0,0 +1,126 @@
+package mediarequests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+// Move this to the config file
+const baseURL = "https://wikimedia.org/api/rest_v1/metrics/mediarequests"
+const uploadHost = "upload.wikimedia.org"
+
+type Items struct {
+	Items []Item
+}
+
+// Item holds both shapes the media requests API returns: per-file counts and top lists
+type Item struct {
+	Referer     string
+	FilePath    string `json:"file_path"`
+	Granularity string
+	Timestamp   string
+	Agent       string
+	Requests    int
+	Files       []File
+}
+
+type File struct {
+	FilePath string `json:"file_path"`
+	Requests int
+	Rank     int
+}
+
+// TopFile is the response shape of the top files, following the shape of the top articles
+type TopFile struct {
+	File     string
+	Requests int
+	Rank     int
+}
+
+// The API expects the upload path of the file (e.g. /wikipedia/commons/a/a9/Example.jpg) as a single
+// URL-encoded path segment. Inputs can be full upload URLs, and are expected to be decoded already (the route
+// decodes the path once), so a % in the input is a % of the file name.
+func escapeFilePath(file string) string {
+	file = strings.TrimPrefix(file, "https://")
+	file = strings.TrimPrefix(file, "http://")
+	file = strings.TrimPrefix(file, uploadHost)
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file
+	}
+	return url.PathEscape(file)
+}
+
+// curl http://localhost:8080/file/wikipedia/commons/a/a9/Example.jpg/monthly/2023/04
+func GetRequests(file string, p period.Period) (int, error) {
+	// Build URL
+	firstDay := utilities.FormatDate(p.Start)
+	lastDay := utilities.FormatDate(p.End)
+	url := fmt.Sprintf("%s/per-file/all-referers/all-agents/%s/daily/%s/%s", baseURL, escapeFilePath(file), firstDay, lastDay)
+
+	// Call the wikipedia API
+	responseData, err := utilities.CallAPI(url)
+	if err != nil {
+		return 0, err
+	}
+
+	// Aggregate request counts
+	var items Items
+	err = json.Unmarshal(responseData, &items)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0
+	for _, item := range items.Items {
+		sum += item.Requests
+	}
+
+	return sum, nil
+}
+
+// curl http://localhost:8080/files/top/monthly/2023/04
+// The wikipedia API only ranks files per month, so the period is expected to be a month
+func GetTopFilesByMonth(p period.Period) (string, error) {
+	// Build URL
+	url := fmt.Sprintf("%s/top/all-referers/all-media-types/%s/all-days", baseURL, p.Start.Format("2006/01"))
+
+	// Call the wikipedia API
+	responseData, err := utilities.CallAPI(url)
+	if err != nil {
+		return "", err
+	}
+
+	// Get top 10 files
+	var items Items
+	err = json.Unmarshal(responseData, &items)
+	if err != nil {
+		return "", err
+	}
+
+	var top10Files []TopFile
+	if len(items.Items) > 0 {
+		numOfFiles := len(items.Items[0].Files)
+		if numOfFiles > 10 {
+			numOfFiles = 10
+		}
+		for _, file := range items.Items[0].Files[0:numOfFiles] {
+			top10Files = append(top10Files, TopFile{
+				File:     file.FilePath,
+				Requests: file.Requests,
+				Rank:     file.Rank,
+			})
+		}
+	}
+
+	// Convert to JSON and return string
+	jsonResult, err := json.Marshal(top10Files)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonResult), nil
+}
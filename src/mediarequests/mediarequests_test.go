@@ -0,0 +1,45 @@
+package mediarequests
+
+import (
+	"testing"
+)
+
+func TestEscapeFilePath(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "upload path with leading slash",
+			input:          "/wikipedia/commons/a/a9/Example.jpg",
+			expectedOutput: "%2Fwikipedia%2Fcommons%2Fa%2Fa9%2FExample.jpg",
+		},
+		{
+			name:           "upload path without leading slash (as matched by the route)",
+			input:          "wikipedia/commons/a/a9/Example.jpg",
+			expectedOutput: "%2Fwikipedia%2Fcommons%2Fa%2Fa9%2FExample.jpg",
+		},
+		{
+			name:           "full upload URL",
+			input:          "https://upload.wikimedia.org/wikipedia/commons/a/a9/Example.jpg",
+			expectedOutput: "%2Fwikipedia%2Fcommons%2Fa%2Fa9%2FExample.jpg",
+		},
+		{
+			name:           "a % of the file name is encoded",
+			input:          "/wikipedia/commons/5/5f/100%_Pure.jpg",
+			expectedOutput: "%2Fwikipedia%2Fcommons%2F5%2F5f%2F100%25_Pure.jpg",
+		},
+		{
+			name:           "unicode file name with reserved characters",
+			input:          "/wikipedia/commons/1/1e/Æthelred (II)?.jpg",
+			expectedOutput: "%2Fwikipedia%2Fcommons%2F1%2F1e%2F%C3%86thelred%20%28II%29%3F.jpg",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := escapeFilePath(tc.input)
+		if got != tc.expectedOutput {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got, tc.expectedOutput)
+		}
+	}
+}
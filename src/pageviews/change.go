@@ -0,0 +1,36 @@
+package pageviews
+
+import (
+	"math"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+)
+
+// Change describes how the views of a period compare with the views of an earlier period
+type Change struct {
+	// Label, first and last days of the earlier period
+	Period    string
+	Start     string
+	End       string
+	Pageviews int
+	// Views of the period minus the views of the earlier period
+	Change int
+	// Change in percent of the views of the earlier period, rounded to two decimals
+	// Null when the earlier period has no views
+	PercentChange *float64
+}
+
+func NewChange(views int, earlier period.Period, earlierViews int) Change {
+	change := Change{
+		Period:    earlier.Label,
+		Start:     earlier.Start.Format("2006-01-02"),
+		End:       earlier.End.Format("2006-01-02"),
+		Pageviews: earlierViews,
+		Change:    views - earlierViews,
+	}
+	if earlierViews != 0 {
+		percent := math.Round(float64(change.Change)/float64(earlierViews)*10000) / 100
+		change.PercentChange = &percent
+	}
+	return change
+}
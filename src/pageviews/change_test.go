@@ -0,0 +1,55 @@
+package pageviews
+
+import (
+	"testing"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChange(t *testing.T) {
+	march, err := period.Month("2023", "03")
+	require.NoError(t, err)
+	testCases := []struct {
+		name                  string
+		views                 int
+		earlierViews          int
+		expectedChange        int
+		expectedPercentChange interface{}
+	}{
+		{
+			name:                  "views went up",
+			views:                 1500,
+			earlierViews:          1200,
+			expectedChange:        300,
+			expectedPercentChange: 25.0,
+		},
+		{
+			name:                  "views went down, rounded to two decimals",
+			views:                 200,
+			earlierViews:          300,
+			expectedChange:        -100,
+			expectedPercentChange: -33.33,
+		},
+		{
+			name:                  "no views in the earlier period",
+			views:                 50,
+			earlierViews:          0,
+			expectedChange:        50,
+			expectedPercentChange: nil,
+		},
+	}
+	for i, tc := range testCases {
+		got := NewChange(tc.views, march, tc.earlierViews)
+		assertResponseField(t, i, got.Period, "2023-03")
+		assertResponseField(t, i, got.Start, "2023-03-01")
+		assertResponseField(t, i, got.End, "2023-03-31")
+		assertResponseField(t, i, got.Change, tc.expectedChange)
+		if tc.expectedPercentChange == nil {
+			require.Nil(t, got.PercentChange)
+		} else {
+			require.NotNil(t, got.PercentChange)
+			assertResponseField(t, i, *got.PercentChange, tc.expectedPercentChange)
+		}
+	}
+}
@@ -3,14 +3,71 @@ package pageviews
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"strconv"
+	"net/url"
+	"sync"
+	"time"
 
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
 	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
 )
 
-const baseURL = "https://wikimedia.org/api/rest_v1/metrics/pageviews/per-article/en.wikipedia/all-access/all-agents"
+// A variable so tests can serve the series from a local server
+var baseURL = "https://wikimedia.org/api/rest_v1/metrics/pageviews"
+
+// Number of series fetched at the same time when the views of several titles are merged, e.g. an article with
+// hundreds of redirects
+const maxConcurrentSeries = 10
+
+// Agents of the wikipedia API, user excludes the views of crawlers (spider) and bots (automated)
+const (
+	AllAgents = "all-agents"
+	User      = "user"
+	Spider    = "spider"
+	Automated = "automated"
+)
+
+const (
+	Hourly  = "hourly"
+	Daily   = "daily"
+	Monthly = "monthly"
+)
+
+// Query describes the time series of an article to retrieve, between the two dates (both inclusive)
+type Query struct {
+	Article     string
+	Start       time.Time
+	End         time.Time
+	Granularity string
+	// Time zone of the days and months of the series, nil for UTC
+	// The wikipedia API counts days in UTC, so for other time zones the hourly views are added up per local day,
+	// which is only possible for projects. Hourly series are always in UTC
+	Location *time.Location
+	// Whose views are counted, all agents if empty
+	Agent string
+	// Wiki of the article, e.g. de.wikipedia, English Wikipedia if empty
+	Project string
+}
+
+func (query Query) project() string {
+	if query.Project == "" {
+		return filter.DefaultProject
+	}
+	return query.Project
+}
+
+// Returns the agent of the input, all agents if the input is empty
+func ParseAgent(input string) (string, error) {
+	switch input {
+	case "":
+		return AllAgents, nil
+	case AllAgents, User, Spider, Automated:
+		return input, nil
+	}
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	return "", fmt.Errorf(status400+": agent must be one of %s, %s, %s, %s", AllAgents, User, Spider, Automated)
+}
 
 type Items struct {
 	Items []Item
@@ -26,202 +83,279 @@ type Item struct {
 	Views       int
 }
 
-// curl http://localhost:8080/article/Albert_Einstein/weekly/2023/03
-func GetPageviewsByWeek(article, year, week string) (int, error) {
-	// Convert input year and week to integers
-	yearInt, err := strconv.Atoi(year)
-	if err != nil {
-		return 0, err
-	}
-	weekInt, err := strconv.Atoi(week)
-	if err != nil {
-		return 0, err
+// curl http://localhost:8080/article/Albert_Einstein/weekly/2023/03?weekScheme=iso
+// curl http://localhost:8080/article/Albert_Einstein/monthly/2023/04
+// curl http://localhost:8080/article/Albert_Einstein/quarterly/2023/1
+// Returns the total pageviews of the titles (an article and optionally its redirects) over the period
+// Periods made of whole months are read from the monthly data, which takes a single small response
+func GetPageviews(titles []string, p period.Period) (int, error) {
+	granularity := Daily
+	if p.MonthAligned() && p.Location == nil {
+		granularity = Monthly
 	}
-
-	// Validate that input year is not out of bounds
-	err = utilities.ValidateInputYear(yearInt)
+	series, err := GetMergedSeries(titles, Query{Start: p.Start, End: p.End, Granularity: granularity, Location: p.Location})
 	if err != nil {
 		return 0, err
 	}
 
-	// Validate that input week is not out of bounds
-	err = utilities.ValidateInputWeek(year, weekInt)
-	if err != nil {
-		return 0, err
+	sum := 0
+	for _, item := range series {
+		sum += item.Views
 	}
+	return sum, nil
+}
 
-	// Get the first and last days of the week
-	startDate := utilities.WeekStart(yearInt, weekInt)
-	endDate := startDate.AddDate(0, 0, 6)
-
-	// Build URL
-	// Wikipedia API expects months and days as 2 digits each so add a zero at the beginning if needed (done by PadString())
-	startDateMonth := utilities.PadString(fmt.Sprint(int(startDate.Month())))
-	startDateDay := utilities.PadString(fmt.Sprint(startDate.Day()))
-	endDateMonth := utilities.PadString(fmt.Sprint(int(endDate.Month())))
-	endDateDay := utilities.PadString(fmt.Sprint(endDate.Day()))
-	firstDay := fmt.Sprint(startDate.Year()) + startDateMonth + startDateDay + "00"
-	lastDay := fmt.Sprint(endDate.Year()) + endDateMonth + endDateDay + "00"
-	url := fmt.Sprintf("%s/%s/daily/%s/%s", baseURL, article, firstDay, lastDay)
-
-	// Call the wikipedia API
-	response, err := http.Get(url)
-	if err != nil {
-		return 0, err
+// curl http://localhost:8080/project/views/monthly/2023/04
+// Returns the total pageviews of all the articles of the project over the period
+func GetProjectPageviews(p period.Period, agent string) (int, error) {
+	granularity := Daily
+	if p.MonthAligned() && p.Location == nil {
+		granularity = Monthly
 	}
-
-	// Parse response
-	responseData, err := io.ReadAll(response.Body)
+	series, err := GetProjectSeries(Query{Start: p.Start, End: p.End, Granularity: granularity, Location: p.Location, Agent: agent})
 	if err != nil {
 		return 0, err
 	}
 
-	// If the request was not successful parse the response for the error and return it
-	if response.StatusCode != http.StatusOK {
-		// return error
-		errorDetails, err := utilities.ParseErrorDetails(responseData)
-		if err != nil {
-			fmt.Print(err.Error())
-			return 0, fmt.Errorf(response.Status + ": Failed to process error details")
-		}
-		return 0, fmt.Errorf(response.Status + ": " + errorDetails)
-	}
-
-	// Aggregate view counts
-	var items Items
-	err = json.Unmarshal(responseData, &items)
-	if err != nil {
-		return 0, err
-	}
 	sum := 0
-	for _, item := range items.Items {
+	for _, item := range series {
 		sum += item.Views
 	}
-
 	return sum, nil
 }
 
-// curl http://localhost:8080/article/Albert_Einstein/monthly/2023/04
-func GetPageviewsByMonth(article, year, month string) (int, error) {
-	// Convert input year to integer
-	yearInt, err := strconv.Atoi(year)
+// curl http://localhost:8080/article/Albert_Einstein/top/monthly/2023/04
+// curl http://localhost:8080/article/Albert_Einstein/top/yearly/2023?by=month
+// Returns the day (or month, depending on the granularity) of the period with the most pageviews of the titles
+// A period without any views is an error rather than a top day with 0 views
+func GetTopOfPeriod(titles []string, p period.Period, granularity string) (string, int, error) {
+	query := Query{Start: p.Start, End: p.End, Granularity: granularity, Location: p.Location}
+	series, err := GetMergedSeries(titles, query)
 	if err != nil {
-		return 0, err
+		return "", 0, err
 	}
+	timestamp, views := TopItem(series)
+	if views == 0 {
+		return "", 0, noData(titles[0], query)
+	}
+	return timestamp, views, nil
+}
 
-	// Validate that input year is not out of bounds
-	err = utilities.ValidateInputYear(yearInt)
-	if err != nil {
-		return 0, err
+// Returns the pageviews of an article for every day (or month) of the query
+// Days that the wikipedia API does not return are filled with zero views so the series has no gaps
+// The wikipedia API only has daily and monthly views per article, so hourly series and time zones (which are
+// added up from hourly views) are only available for projects
+func GetSeries(query Query) ([]Item, error) {
+	if query.Granularity == Hourly || query.Location != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400 + ": the wikipedia API has no hourly views per article, hourly views and time zones are only supported for the project")
 	}
+	return getSeries(query, articleURL)
+}
+
+// Returns the pageviews of all the articles of the project of the query for every day (or month) of the query, the article
+// of the query is ignored
+func GetProjectSeries(query Query) ([]Item, error) {
+	return getSeries(query, projectURL)
+}
 
-	lastOfMonth, err := utilities.LastDayOfMonth(year, month)
+func articleURL(query Query, agent, granularity, first, last string) string {
+	return fmt.Sprintf("%s/per-article/%s/all-access/%s/%s/%s/%s/%s", baseURL, query.project(), agent, url.PathEscape(query.Article), granularity, first, last)
+}
+
+func projectURL(query Query, agent, granularity, first, last string) string {
+	return fmt.Sprintf("%s/aggregate/%s/all-access/%s/%s/%s/%s", baseURL, query.project(), agent, granularity, first, last)
+}
+
+// Fetches the series of the query from the URL built by buildURL for the agent, granularity and first and last
+// timestamps of the call
+func getSeries(query Query, buildURL func(query Query, agent, granularity, first, last string) string) ([]Item, error) {
+	timestamps, err := Timestamps(query)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Build URL
-	month = utilities.PadString(month)
-	firstDay := year + month + "0100"
-	lastDay := year + month + fmt.Sprint(lastOfMonth.Day()) + "00"
-	url := fmt.Sprintf("%s/%s/monthly/%s/%s", baseURL, article, firstDay, lastDay)
+	granularity := query.Granularity
+	firstDay := timestamps[0]
+	lastDay := period.Timestamp(query.End)
+	if granularity == Hourly {
+		lastDay = timestamps[len(timestamps)-1]
+	} else if query.Location != nil {
+		granularity = Hourly
+		firstHour, lastHour := hourRange(query)
+		firstDay, lastDay = period.Timestamp(firstHour), period.Timestamp(lastHour)
+	}
+	agent := query.Agent
+	if agent == "" {
+		agent = AllAgents
+	}
+	url := buildURL(query, agent, granularity, firstDay, lastDay)
 
 	// Call the wikipedia API
-	response, err := http.Get(url)
+	responseData, err := utilities.CallAPI(url)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	// Parse response
-	responseData, err := io.ReadAll(response.Body)
+	var items Items
+	err = json.Unmarshal(responseData, &items)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	// If the request was not successful parse the response for the error and return it
-	if response.StatusCode != http.StatusOK {
-		// return error
-		errorDetails, err := utilities.ParseErrorDetails(responseData)
-		if err != nil {
-			fmt.Print(err.Error())
-			return 0, fmt.Errorf(response.Status + ": Failed to process error details")
-		}
-		return 0, fmt.Errorf(response.Status + ": " + errorDetails)
+	if query.Location != nil && query.Granularity != Hourly {
+		items.Items = bucketHours(query, items.Items)
 	}
+	return fillSeries(query, timestamps, items.Items), nil
+}
 
-	// Parse response and retrieve pageviews number
-	var items Items
-	err = json.Unmarshal(responseData, &items)
-	if err != nil {
-		return 0, err
+// Returns the first and last UTC hours that start in the local days of the query
+// Local days are 23 or 25 hours long when daylight saving time starts or ends, and start in the middle of a UTC
+// hour in time zones that are not a whole number of hours away from UTC (e.g. +05:30)
+func hourRange(query Query) (time.Time, time.Time) {
+	start := query.Start
+	if query.Granularity == Monthly {
+		start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
 	}
-
-	return items.Items[0].Views, nil
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, query.Location).UTC()
+	nextDay := time.Date(query.End.Year(), query.End.Month(), query.End.Day()+1, 0, 0, 0, 0, query.Location).UTC()
+	firstHour := firstDay.Truncate(time.Hour)
+	if firstHour.Before(firstDay) {
+		firstHour = firstHour.Add(time.Hour)
+	}
+	return firstHour, nextDay.Add(-time.Minute).Truncate(time.Hour)
 }
 
-// curl http://localhost:8080/article/Albert_Einstein/top/monthly/2023/04
-func GetDayWithMostPageviews(article, year, month string) (string, int, error) {
-	// Convert input year to integer
-	yearInt, err := strconv.Atoi(year)
-	if err != nil {
-		return "", 0, err
+// Moves every hourly item to the local day (or month) of the query it belongs to
+// Hours are counted in the day their start falls in, which only matters for time zones that are not a whole
+// number of hours away from UTC
+func bucketHours(query Query, items []Item) []Item {
+	bucketed := make([]Item, 0, len(items))
+	for _, item := range items {
+		hour, err := time.Parse("2006010215", item.Timestamp)
+		if err != nil {
+			continue
+		}
+		local := hour.In(query.Location)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		if query.Granularity == Monthly {
+			day = day.AddDate(0, 0, 1-day.Day())
+		}
+		item.Timestamp = period.Timestamp(day)
+		bucketed = append(bucketed, item)
 	}
+	return bucketed
+}
 
-	// Validate that input year is not out of bounds
-	err = utilities.ValidateInputYear(yearInt)
-	if err != nil {
-		return "", 0, err
+// Returns the series of the first title with the views of all the titles added up, e.g. an article and its
+// redirects. Titles without any data in the period count as zero views, unless none of the titles has data.
+func GetMergedSeries(titles []string, query Query) ([]Item, error) {
+	seriesByTitle := make([][]Item, len(titles))
+	errs := make([]error, len(titles))
+	slots := make(chan struct{}, maxConcurrentSeries)
+	var wg sync.WaitGroup
+	for i, title := range titles {
+		wg.Add(1)
+		go func(i int, title string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			titleQuery := query
+			titleQuery.Article = title
+			seriesByTitle[i], errs[i] = GetSeries(titleQuery)
+		}(i, title)
+	}
+	wg.Wait()
+
+	var merged []Item
+	var notFound error
+	for i, series := range seriesByTitle {
+		if errs[i] != nil {
+			if !utilities.IsNotFound(errs[i]) {
+				return nil, errs[i]
+			}
+			notFound = errs[i]
+			continue
+		}
+		if merged == nil {
+			merged = series
+			continue
+		}
+		for j := range series {
+			merged[j].Views += series[j].Views
+		}
+	}
+	if merged == nil {
+		return nil, notFound
 	}
 
-	// Get month's last day
-	lastOfMonth, err := utilities.LastDayOfMonth(year, month)
-	if err != nil {
-		return "", 0, err
+	for i := range merged {
+		merged[i].Article = titles[0]
 	}
+	return merged, nil
+}
 
-	// Build URL
-	month = utilities.PadString(month)
-	firstDay := year + month + "0100"
-	lastDay := year + month + fmt.Sprint(lastOfMonth.Day()) + "00"
-	url := fmt.Sprintf("%s/%s/daily/%s/%s", baseURL, article, firstDay, lastDay)
+func fillSeries(query Query, timestamps []string, items []Item) []Item {
+	viewsByTimestamp := map[string]int{}
+	for _, item := range items {
+		viewsByTimestamp[item.Timestamp] += item.Views
+	}
 
-	// Call the wikipedia API
-	response, err := http.Get(url)
-	if err != nil {
-		return "", 0, err
+	series := make([]Item, len(timestamps))
+	for i, timestamp := range timestamps {
+		series[i] = Item{
+			Article:     query.Article,
+			Granularity: query.Granularity,
+			Timestamp:   timestamp,
+			Views:       viewsByTimestamp[timestamp],
+		}
 	}
+	return series
+}
 
-	// Parse response
-	responseData, err := io.ReadAll(response.Body)
-	if err != nil {
-		return "", 0, err
+// Returns the timestamps (in the format of the wikipedia API) that a series for the query is expected to have
+// For monthly series the timestamps are the first day of every month that overlaps with the query, and hourly
+// series cover every hour of the days of the query
+func Timestamps(query Query) ([]string, error) {
+	if query.Start.After(query.End) {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400 + ": start date cannot be after end date")
 	}
 
-	// If the request was not successful parse the response for the error and return it
-	if response.StatusCode != http.StatusOK {
-		// return error
-		errorDetails, err := utilities.ParseErrorDetails(responseData)
-		if err != nil {
-			fmt.Print(err.Error())
-			return "", 0, fmt.Errorf(response.Status + ": Failed to process error details")
-		}
-		return "", 0, fmt.Errorf(response.Status + ": " + errorDetails)
+	var step func(time.Time) time.Time
+	date := query.Start
+	last := query.End
+	switch query.Granularity {
+	case Hourly:
+		last = last.Add(23 * time.Hour)
+		step = func(t time.Time) time.Time { return t.Add(time.Hour) }
+	case Daily:
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case Monthly:
+		date = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		step = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400+": granularity must be %s, %s or %s", Hourly, Daily, Monthly)
 	}
 
-	// Loop through results and find the max pageviews
-	var topDay string
-	var items Items
-	err = json.Unmarshal(responseData, &items)
-	if err != nil {
-		return "", 0, err
+	var timestamps []string
+	for ; !date.After(last); date = step(date) {
+		timestamps = append(timestamps, period.Timestamp(date))
 	}
+	return timestamps, nil
+}
+
+// Returns the timestamp and the views of the item with the most views
+func TopItem(items []Item) (string, int) {
+	var topTimestamp string
 	topPageviews := 0
-	for _, item := range items.Items {
+	for _, item := range items {
 		if item.Views > topPageviews {
 			topPageviews = item.Views
-			topDay = item.Timestamp
+			topTimestamp = item.Timestamp
 		}
 	}
-
-	return topDay, topPageviews, nil
+	return topTimestamp, topPageviews
 }
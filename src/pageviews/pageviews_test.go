@@ -1,12 +1,20 @@
 package pageviews
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
 	"github.com/stretchr/testify/require"
 )
 
-func TestGetPageviewsByWeek(t *testing.T) {
+func TestGetPageviewsWeekly(t *testing.T) {
 	testCases := []struct {
 		name              string
 		article           string
@@ -65,7 +73,11 @@ func TestGetPageviewsByWeek(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
-		gotPageviews, gotError := GetPageviewsByWeek(tc.article, tc.year, tc.week)
+		var gotPageviews int
+		p, gotError := period.Week(tc.year, tc.week, utilities.ISOWeek)
+		if gotError == nil {
+			gotPageviews, gotError = GetPageviews([]string{tc.article}, p)
+		}
 		if tc.expectedError != "" {
 			require.Error(t, gotError)
 			assertResponseField(t, i, gotError.Error(), tc.expectedError)
@@ -76,7 +88,7 @@ func TestGetPageviewsByWeek(t *testing.T) {
 	}
 }
 
-func TestGetPageviewsByMonth(t *testing.T) {
+func TestGetPageviewsMonthly(t *testing.T) {
 	testCases := []struct {
 		name              string
 		article           string
@@ -99,7 +111,7 @@ func TestGetPageviewsByMonth(t *testing.T) {
 			year:              "2023",
 			month:             "14",
 			expectedPageviews: 0,
-			expectedError:     "400 Bad Request: start timestamp is invalid, must be a valid date in YYYYMMDD format",
+			expectedError:     "400 Bad Request: input month must be between 1 and 12",
 		},
 		{
 			name:              "error case: HTTP 400 for invalid input (year > current year)",
@@ -119,7 +131,11 @@ func TestGetPageviewsByMonth(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
-		gotPageviews, gotError := GetPageviewsByMonth(tc.article, tc.year, tc.month)
+		var gotPageviews int
+		p, gotError := period.Month(tc.year, tc.month)
+		if gotError == nil {
+			gotPageviews, gotError = GetPageviews([]string{tc.article}, p)
+		}
 		if tc.expectedError != "" {
 			require.Error(t, gotError)
 			assertResponseField(t, i, gotError.Error(), tc.expectedError)
@@ -130,7 +146,7 @@ func TestGetPageviewsByMonth(t *testing.T) {
 	}
 }
 
-func TestGetDayWithMostPageviews(t *testing.T) {
+func TestGetTopOfPeriod(t *testing.T) {
 	testCases := []struct {
 		name              string
 		article           string
@@ -156,7 +172,7 @@ func TestGetDayWithMostPageviews(t *testing.T) {
 			month:             "14",
 			expectedDay:       "",
 			expectedPageviews: 0,
-			expectedError:     "400 Bad Request: start timestamp is invalid, must be a valid date in YYYYMMDD format",
+			expectedError:     "400 Bad Request: input month must be between 1 and 12",
 		},
 		{
 			name:              "error case: HTTP 400 for invalid input (year > current year)",
@@ -178,7 +194,12 @@ func TestGetDayWithMostPageviews(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
-		gotDay, gotPageviews, gotError := GetDayWithMostPageviews(tc.article, tc.year, tc.month)
+		var gotDay string
+		var gotPageviews int
+		p, gotError := period.Month(tc.year, tc.month)
+		if gotError == nil {
+			gotDay, gotPageviews, gotError = GetTopOfPeriod([]string{tc.article}, p, Daily)
+		}
 		if tc.expectedError != "" {
 			require.Error(t, gotError)
 			assertResponseField(t, i, gotError.Error(), tc.expectedError)
@@ -190,6 +211,227 @@ func TestGetDayWithMostPageviews(t *testing.T) {
 	}
 }
 
+func TestTimestamps(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              Query
+		expectedTimestamps []string
+	}{
+		{
+			name: "daily timestamps across months",
+			query: Query{
+				Start:       time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC),
+				End:         time.Date(2023, 2, 2, 0, 0, 0, 0, time.UTC),
+				Granularity: Daily,
+			},
+			expectedTimestamps: []string{"2023013000", "2023013100", "2023020100", "2023020200"},
+		},
+		{
+			name: "monthly timestamps start on the first day of the month",
+			query: Query{
+				Start:       time.Date(2022, 11, 15, 0, 0, 0, 0, time.UTC),
+				End:         time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+				Granularity: Monthly,
+			},
+			expectedTimestamps: []string{"2022110100", "2022120100", "2023010100"},
+		},
+	}
+	for i, tc := range testCases {
+		got, err := Timestamps(tc.query)
+		require.NoError(t, err)
+		if !reflect.DeepEqual(got, tc.expectedTimestamps) {
+			t.Errorf("test %d failed: got %v want %v", i+1, got, tc.expectedTimestamps)
+		}
+	}
+}
+
+func TestFillSeries(t *testing.T) {
+	query := Query{
+		Article:     "ChatGPT",
+		Start:       time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC),
+		Granularity: Daily,
+	}
+	timestamps, err := Timestamps(query)
+	require.NoError(t, err)
+
+	// the wikipedia API does not return the days without views
+	items := []Item{
+		{Article: "ChatGPT", Timestamp: "2023030100", Views: 10},
+		{Article: "ChatGPT", Timestamp: "2023030300", Views: 30},
+	}
+	got := fillSeries(query, timestamps, items)
+	assertResponseField(t, 0, len(got), 3)
+	assertResponseField(t, 1, got[1].Timestamp, "2023030200")
+	assertResponseField(t, 2, got[1].Views, 0)
+	assertResponseField(t, 3, got[2].Views, 30)
+}
+
+func TestHourRange(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	testCases := []struct {
+		name              string
+		location          *time.Location
+		start             time.Time
+		end               time.Time
+		expectedFirstHour string
+		expectedLastHour  string
+	}{
+		{
+			name:              "a day in winter (UTC-8)",
+			location:          losAngeles,
+			start:             time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+			end:               time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+			expectedFirstHour: "2023011008",
+			expectedLastHour:  "2023011107",
+		},
+		{
+			name:              "the day daylight saving time starts has 23 hours",
+			location:          losAngeles,
+			start:             time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC),
+			end:               time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC),
+			expectedFirstHour: "2023031208",
+			expectedLastHour:  "2023031306",
+		},
+		{
+			name:              "the day daylight saving time ends has 25 hours",
+			location:          losAngeles,
+			start:             time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC),
+			end:               time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC),
+			expectedFirstHour: "2023110507",
+			expectedLastHour:  "2023110607",
+		},
+		{
+			// Local midnight is at 18:30 UTC, so the day has the hours starting at 19:00 UTC the day before up to
+			// the hour starting at 18:00 UTC (23:30 local time)
+			name:              "a day half an hour off the UTC hours (UTC+5:30)",
+			location:          kolkata,
+			start:             time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+			end:               time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+			expectedFirstHour: "2023010919",
+			expectedLastHour:  "2023011018",
+		},
+	}
+	for i, tc := range testCases {
+		firstHour, lastHour := hourRange(Query{Start: tc.start, End: tc.end, Granularity: Daily, Location: tc.location})
+		assertResponseField(t, i, firstHour.Format("2006010215"), tc.expectedFirstHour)
+		assertResponseField(t, i, lastHour.Format("2006010215"), tc.expectedLastHour)
+	}
+}
+
+// Pins the URLs of the wikipedia API that the series are read from, which are served from a local server
+func TestSeriesURL(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.EscapedPath())
+		fmt.Fprint(w, `{"items":[{"timestamp":"2023031208","views":2},{"timestamp":"2023031306","views":4}]}`)
+	}))
+	defer server.Close()
+	defaultURL := baseURL
+	baseURL = server.URL
+	defer func() { baseURL = defaultURL }()
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	day := time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		getSeries     func(Query) ([]Item, error)
+		query         Query
+		expectedURL   string
+		expectedError string
+	}{
+		{
+			name:        "daily views of an article",
+			getSeries:   GetSeries,
+			query:       Query{Article: "AC/DC", Start: day, End: day, Granularity: Daily, Agent: User},
+			expectedURL: "/per-article/en.wikipedia/all-access/user/AC%2FDC/daily/2023031200/2023031200",
+		},
+		{
+			name:        "monthly views of an article of another project",
+			getSeries:   GetSeries,
+			query:       Query{Article: "Albert_Einstein", Start: day, End: day, Granularity: Monthly, Project: "de.wikipedia"},
+			expectedURL: "/per-article/de.wikipedia/all-access/all-agents/Albert_Einstein/monthly/2023030100/2023031200",
+		},
+		{
+			name:          "hourly views of an article",
+			getSeries:     GetSeries,
+			query:         Query{Article: "ChatGPT", Start: day, End: day, Granularity: Hourly},
+			expectedError: "400 Bad Request: the wikipedia API has no hourly views per article, hourly views and time zones are only supported for the project",
+		},
+		{
+			name:          "daily views of an article in a time zone",
+			getSeries:     GetSeries,
+			query:         Query{Article: "ChatGPT", Start: day, End: day, Granularity: Daily, Location: losAngeles},
+			expectedError: "400 Bad Request: the wikipedia API has no hourly views per article, hourly views and time zones are only supported for the project",
+		},
+		{
+			name:        "hourly views of the project",
+			getSeries:   GetProjectSeries,
+			query:       Query{Start: day, End: day, Granularity: Hourly},
+			expectedURL: "/aggregate/en.wikipedia/all-access/all-agents/hourly/2023031200/2023031223",
+		},
+		{
+			name:        "daily views of the project in a time zone are read from the hours of the local day",
+			getSeries:   GetProjectSeries,
+			query:       Query{Start: day, End: day, Granularity: Daily, Location: losAngeles},
+			expectedURL: "/aggregate/en.wikipedia/all-access/all-agents/hourly/2023031208/2023031306",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requested = nil
+			_, err := tc.getSeries(tc.query)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				require.Empty(t, requested)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{tc.expectedURL}, requested)
+		})
+	}
+}
+
+func TestBucketHours(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	query := Query{
+		Article:     "ChatGPT",
+		Start:       time.Date(2023, 3, 11, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC),
+		Granularity: Daily,
+		Location:    losAngeles,
+	}
+	timestamps, err := Timestamps(query)
+	require.NoError(t, err)
+
+	items := []Item{
+		// 23:00 on March 11 in Los Angeles (UTC-8)
+		{Article: "ChatGPT", Timestamp: "2023031207", Views: 1},
+		// midnight on March 12 in Los Angeles
+		{Article: "ChatGPT", Timestamp: "2023031208", Views: 2},
+		// 23:00 on March 12 in Los Angeles, which is UTC-7 after 2:00
+		{Article: "ChatGPT", Timestamp: "2023031306", Views: 4},
+	}
+	got := fillSeries(query, timestamps, bucketHours(query, items))
+	assertResponseField(t, 0, len(got), 2)
+	assertResponseField(t, 1, got[0].Timestamp, "2023031100")
+	assertResponseField(t, 2, got[0].Views, 1)
+	assertResponseField(t, 3, got[1].Timestamp, "2023031200")
+	assertResponseField(t, 4, got[1].Views, 6)
+
+	query.Granularity = Monthly
+	timestamps, err = Timestamps(query)
+	require.NoError(t, err)
+	got = fillSeries(query, timestamps, bucketHours(query, items))
+	assertResponseField(t, 5, len(got), 1)
+	assertResponseField(t, 6, got[0].Views, 7)
+}
+
 func assertResponseField(t testing.TB, testNum int, got, want interface{}) {
 	t.Helper()
 	if got != want {
@@ -0,0 +1,120 @@
+package pageviews
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/period"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+// The wikipedia API has no weekly series, weeks are added up from the daily series
+const Weekly = "weekly"
+
+const MaxPeaks = 100
+
+type Peak struct {
+	Timestamp string
+	Views     int
+	// Peaks with the same views share the same rank
+	Rank int
+}
+
+type Peaks struct {
+	Article     string
+	Granularity string
+	Peaks       []Peak
+}
+
+// curl "http://localhost:8080/article/Albert_Einstein/peaks?start=20230101&end=20230331&granularity=daily&n=5"
+// Returns the days, weeks or months of the query with the most views of the titles
+// The wikipedia API has no hourly views per article, so there are no hourly peaks
+// Weeks are labelled with their first day for the scheme, weeks cut by the start or end of the query only count
+// the days of the query
+func GetPeaks(titles []string, query Query, n int, scheme utilities.WeekScheme) (Peaks, error) {
+	if n < 1 || n > MaxPeaks {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Peaks{}, fmt.Errorf(status400+": n must be between 1 and %d", MaxPeaks)
+	}
+	granularity := query.Granularity
+	switch granularity {
+	case Daily, Monthly:
+	case Weekly:
+		query.Granularity = Daily
+	default:
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Peaks{}, fmt.Errorf(status400+": granularity must be %s, %s or %s", Daily, Weekly, Monthly)
+	}
+	series, err := GetMergedSeries(titles, query)
+	if err != nil {
+		return Peaks{}, err
+	}
+	if granularity == Weekly {
+		series = GroupByWeek(series, scheme)
+	}
+
+	peaks := RankPeaks(series, n)
+	if len(peaks) == 0 {
+		return Peaks{}, noData(titles[0], query)
+	}
+	return Peaks{Article: titles[0], Granularity: granularity, Peaks: peaks}, nil
+}
+
+// Returns the n items with the most views, the most viewed first
+// Items with the same views are ordered by timestamp and share the same rank, and the items that have the same
+// views as the last one are all returned, so there can be more than n peaks. Items without views are never peaks.
+func RankPeaks(items []Item, n int) []Peak {
+	sorted := make([]Item, 0, len(items))
+	for _, item := range items {
+		if item.Views > 0 {
+			sorted = append(sorted, item)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Views != sorted[j].Views {
+			return sorted[i].Views > sorted[j].Views
+		}
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	var peaks []Peak
+	for i, item := range sorted {
+		rank := i + 1
+		if i > 0 && item.Views == sorted[i-1].Views {
+			rank = peaks[i-1].Rank
+		}
+		if i >= n && rank > n {
+			break
+		}
+		peaks = append(peaks, Peak{Timestamp: item.Timestamp, Views: item.Views, Rank: rank})
+	}
+	return peaks
+}
+
+// Adds up the daily items per week of the scheme, every week is labelled with its first day
+func GroupByWeek(items []Item, scheme utilities.WeekScheme) []Item {
+	var weeks []Item
+	for _, item := range items {
+		day, err := time.Parse("2006010215", item.Timestamp)
+		if err != nil {
+			continue
+		}
+		weekStart := period.Timestamp(utilities.WeekStartOfDate(day, scheme))
+		if len(weeks) == 0 || weeks[len(weeks)-1].Timestamp != weekStart {
+			item.Timestamp = weekStart
+			item.Granularity = Weekly
+			weeks = append(weeks, item)
+			continue
+		}
+		weeks[len(weeks)-1].Views += item.Views
+	}
+	return weeks
+}
+
+// The error of a period without any views, so it is not mistaken for a peak with 0 views
+func noData(article string, query Query) error {
+	status404 := fmt.Sprint(http.StatusNotFound) + " " + http.StatusText(http.StatusNotFound)
+	return fmt.Errorf(status404+": %s has no pageviews between %s and %s", article, query.Start.Format("2006-01-02"), query.End.Format("2006-01-02"))
+}
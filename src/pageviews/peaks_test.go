@@ -0,0 +1,90 @@
+package pageviews
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankPeaks(t *testing.T) {
+	items := []Item{
+		{Timestamp: "2023030100", Views: 10},
+		{Timestamp: "2023030200", Views: 30},
+		{Timestamp: "2023030300", Views: 20},
+		{Timestamp: "2023030400", Views: 30},
+		{Timestamp: "2023030500", Views: 20},
+		{Timestamp: "2023030600", Views: 0},
+	}
+	testCases := []struct {
+		name          string
+		n             int
+		expectedPeaks []Peak
+	}{
+		{
+			name: "ties share the rank and are ordered by timestamp",
+			n:    2,
+			expectedPeaks: []Peak{
+				{Timestamp: "2023030200", Views: 30, Rank: 1},
+				{Timestamp: "2023030400", Views: 30, Rank: 1},
+			},
+		},
+		{
+			name: "items tied with the last peak are all returned",
+			n:    3,
+			expectedPeaks: []Peak{
+				{Timestamp: "2023030200", Views: 30, Rank: 1},
+				{Timestamp: "2023030400", Views: 30, Rank: 1},
+				{Timestamp: "2023030300", Views: 20, Rank: 3},
+				{Timestamp: "2023030500", Views: 20, Rank: 3},
+			},
+		},
+		{
+			name: "items without views are never peaks",
+			n:    10,
+			expectedPeaks: []Peak{
+				{Timestamp: "2023030200", Views: 30, Rank: 1},
+				{Timestamp: "2023030400", Views: 30, Rank: 1},
+				{Timestamp: "2023030300", Views: 20, Rank: 3},
+				{Timestamp: "2023030500", Views: 20, Rank: 3},
+				{Timestamp: "2023030100", Views: 10, Rank: 5},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedPeaks, RankPeaks(items, tc.n))
+		})
+	}
+
+	require.Empty(t, RankPeaks([]Item{{Timestamp: "2023030100", Views: 0}}, 5))
+}
+
+func TestGroupByWeek(t *testing.T) {
+	query := Query{
+		Start:       time.Date(2023, 2, 25, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC),
+		Granularity: Daily,
+	}
+	timestamps, err := Timestamps(query)
+	require.NoError(t, err)
+	var items []Item
+	for _, timestamp := range timestamps {
+		items = append(items, Item{Timestamp: timestamp, Views: 1})
+	}
+
+	// February 25, 2023 is a Saturday, so the ISO weeks start on February 20 (cut), February 27 and March 6 (cut)
+	got := GroupByWeek(items, utilities.ISOWeek)
+	require.Len(t, got, 3)
+	assertResponseField(t, 0, got[0].Timestamp, "2023022000")
+	assertResponseField(t, 1, got[0].Views, 2)
+	assertResponseField(t, 2, got[1].Views, 7)
+	assertResponseField(t, 3, got[2].Timestamp, "2023030600")
+	assertResponseField(t, 4, got[2].Views, 2)
+
+	got = GroupByWeek(items, utilities.SaturdayWeek)
+	require.Len(t, got, 2)
+	assertResponseField(t, 5, got[0].Timestamp, "2023022500")
+	assertResponseField(t, 6, got[0].Views, 7)
+}
@@ -0,0 +1,102 @@
+package pageviews
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/stats"
+)
+
+var DefaultPercentiles = []float64{5, 25, 50, 75, 95}
+
+// Descriptive statistics of the daily views of an article
+type Summary struct {
+	Article     string
+	Agent       string
+	Days        int
+	Total       int
+	Mean        float64
+	Median      float64
+	StdDev      float64
+	Min         int
+	Max         int
+	Percentiles []PercentileViews
+	// Average daily views of every day of the week, starting on Monday
+	DayOfWeek []DayOfWeekViews
+}
+
+type PercentileViews struct {
+	Percentile float64
+	Views      float64
+}
+
+type DayOfWeekViews struct {
+	Day string
+	// Number of days of the range that fall on this day of the week
+	Days int
+	Mean float64
+}
+
+// curl "http://localhost:8080/article/Albert_Einstein/stats?start=20230101&end=20230331&agent=user"
+// Returns the statistics of the daily views of the titles, days without views count as 0 views
+func GetSummary(titles []string, query Query, percentiles []float64) (Summary, error) {
+	for _, percentile := range percentiles {
+		if percentile < 0 || percentile > 100 {
+			status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+			return Summary{}, fmt.Errorf(status400 + ": percentiles must be between 0 and 100")
+		}
+	}
+	query.Granularity = Daily
+	series, err := GetMergedSeries(titles, query)
+	if err != nil {
+		return Summary{}, err
+	}
+	summary := Describe(series, percentiles)
+	summary.Article = titles[0]
+	summary.Agent = query.Agent
+	if summary.Agent == "" {
+		summary.Agent = AllAgents
+	}
+	return summary, nil
+}
+
+// Returns the statistics of a daily series
+func Describe(series []Item, percentiles []float64) Summary {
+	values := make([]float64, len(series))
+	total := 0
+	byWeekday := make([][]float64, 7)
+	for i, item := range series {
+		values[i] = float64(item.Views)
+		total += item.Views
+		if day, err := time.Parse("2006010215", item.Timestamp); err == nil {
+			byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], values[i])
+		}
+	}
+
+	summary := Summary{
+		Days:   len(series),
+		Total:  total,
+		Mean:   stats.Mean(values),
+		Median: stats.Median(values),
+		StdDev: stats.StdDev(values),
+		Min:    int(stats.Min(values)),
+		Max:    int(stats.Max(values)),
+	}
+	for _, percentile := range percentiles {
+		summary.Percentiles = append(summary.Percentiles, PercentileViews{
+			Percentile: percentile,
+			Views:      stats.Percentile(values, percentile),
+		})
+	}
+	for i := 0; i < 7; i++ {
+		// time.Weekday starts on Sunday
+		weekday := time.Weekday((i + 1) % 7)
+		summary.DayOfWeek = append(summary.DayOfWeek, DayOfWeekViews{
+			Day:  weekday.String(),
+			Days: len(byWeekday[weekday]),
+			Mean: stats.Mean(byWeekday[weekday]),
+		})
+	}
+	return summary
+}
@@ -0,0 +1,50 @@
+package pageviews
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	// March 6, 2023 is a Monday, so the series covers two Mondays and one of every other day
+	query := Query{
+		Start:       time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC),
+		Granularity: Daily,
+	}
+	timestamps, err := Timestamps(query)
+	require.NoError(t, err)
+	views := []int{10, 20, 30, 40, 50, 60, 70, 30}
+	series := make([]Item, len(timestamps))
+	for i, timestamp := range timestamps {
+		series[i] = Item{Timestamp: timestamp, Views: views[i]}
+	}
+
+	got := Describe(series, []float64{25, 50})
+	assertResponseField(t, 0, got.Days, 8)
+	assertResponseField(t, 1, got.Total, 310)
+	assertResponseField(t, 2, got.Mean, 38.75)
+	assertResponseField(t, 3, got.Median, 35.0)
+	assertResponseField(t, 4, got.Min, 10)
+	assertResponseField(t, 5, got.Max, 70)
+	require.Equal(t, []PercentileViews{{Percentile: 25, Views: 27.5}, {Percentile: 50, Views: 35}}, got.Percentiles)
+
+	require.Len(t, got.DayOfWeek, 7)
+	assertResponseField(t, 6, got.DayOfWeek[0], DayOfWeekViews{Day: "Monday", Days: 2, Mean: 20})
+	assertResponseField(t, 7, got.DayOfWeek[6], DayOfWeekViews{Day: "Sunday", Days: 1, Mean: 70})
+}
+
+func TestParseAgent(t *testing.T) {
+	got, err := ParseAgent("")
+	require.NoError(t, err)
+	assertResponseField(t, 0, got, AllAgents)
+
+	got, err = ParseAgent("user")
+	require.NoError(t, err)
+	assertResponseField(t, 1, got, User)
+
+	_, err = ParseAgent("bots")
+	require.EqualError(t, err, "400 Bad Request: agent must be one of all-agents, user, spider, automated")
+}
@@ -0,0 +1,350 @@
+package period
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+)
+
+const (
+	Daily     = "daily"
+	Weekly    = "weekly"
+	Monthly   = "monthly"
+	Quarterly = "quarterly"
+	Yearly    = "yearly"
+	// A period relative to the last day with complete data, e.g. last-7-days
+	Relative = "relative"
+)
+
+// The wikipedia API serves the data of a day some hours after the day ends (UTC)
+const DataLag = 12 * time.Hour
+
+// The longest window of a last-N-days expression
+const MaxRelativeDays = 366
+
+var lastNDays = regexp.MustCompile(`^last-([0-9]+)-days$`)
+
+// Periods a period can be compared with
+const (
+	// The period of the same length right before, e.g. the previous week or month
+	Previous = "previous"
+	// The same period one year earlier
+	YearAgo = "year-ago"
+)
+
+// A Period is a range of whole days, from Start to End (both inclusive)
+type Period struct {
+	Start       time.Time
+	End         time.Time
+	Granularity string
+	// Human readable name of the period, e.g. 2023-Q1
+	Label string
+	// Time zone of the calendar days of the period, nil for UTC which is the time zone of the wikipedia API
+	Location *time.Location
+}
+
+// Loads the time zone of the tz query parameter, e.g. America/Los_Angeles. An empty name is UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400+": unknown time zone %q", name)
+	}
+	if location == time.UTC {
+		return nil, nil
+	}
+	return location, nil
+}
+
+// Builds the period of a route from its variables: a date, a year plus a week, a month or a quarter, only a
+// year, or a relative expression. The week scheme is only used for weeks.
+func FromVars(vars map[string]string, scheme utilities.WeekScheme) (Period, error) {
+	if expression, ok := vars["relative"]; ok {
+		return FromExpression(expression, time.Now())
+	}
+	if date, ok := vars["date"]; ok {
+		return Day(date)
+	}
+	if week, ok := vars["week"]; ok {
+		return Week(vars["year"], week, scheme)
+	}
+	if month, ok := vars["month"]; ok {
+		return Month(vars["year"], month)
+	}
+	if quarter, ok := vars["quarter"]; ok {
+		return Quarter(vars["year"], quarter)
+	}
+	return Year(vars["year"])
+}
+
+// A single day, from a date in the YYYYMMDD format
+func Day(date string) (Period, error) {
+	day, err := utilities.ParseDate(date)
+	if err != nil {
+		return Period{}, err
+	}
+	err = utilities.ValidateInputYear(day.Year())
+	if err != nil {
+		return Period{}, err
+	}
+	return Period{Start: day, End: day, Granularity: Daily, Label: day.Format("2006-01-02")}, nil
+}
+
+// The first and last days of the week depend on the scheme
+func Week(year, week string, scheme utilities.WeekScheme) (Period, error) {
+	yearInt, err := parseYear(year)
+	if err != nil {
+		return Period{}, err
+	}
+	weekInt, err := strconv.Atoi(week)
+	if err != nil {
+		return Period{}, err
+	}
+	err = utilities.ValidateWeekForScheme(yearInt, weekInt, scheme)
+	if err != nil {
+		return Period{}, err
+	}
+	startDate := utilities.WeekStartForScheme(yearInt, weekInt, scheme)
+	return Period{
+		Start:       startDate,
+		End:         startDate.AddDate(0, 0, 6),
+		Granularity: Weekly,
+		Label:       fmt.Sprintf("%d-W%02d", yearInt, weekInt),
+	}, nil
+}
+
+func Month(year, month string) (Period, error) {
+	yearInt, err := parseYear(year)
+	if err != nil {
+		return Period{}, err
+	}
+	monthInt, err := strconv.Atoi(month)
+	if err != nil {
+		return Period{}, err
+	}
+	if monthInt < 1 || monthInt > 12 {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Period{}, fmt.Errorf(status400 + ": input month must be between 1 and 12")
+	}
+	startDate := time.Date(yearInt, time.Month(monthInt), 1, 0, 0, 0, 0, time.UTC)
+	return Period{
+		Start:       startDate,
+		End:         startDate.AddDate(0, 1, -1),
+		Granularity: Monthly,
+		Label:       startDate.Format("2006-01"),
+	}, nil
+}
+
+func Quarter(year, quarter string) (Period, error) {
+	yearInt, err := parseYear(year)
+	if err != nil {
+		return Period{}, err
+	}
+	quarterInt, err := strconv.Atoi(quarter)
+	if err != nil || quarterInt < 1 || quarterInt > 4 {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return Period{}, fmt.Errorf(status400 + ": input quarter must be between 1 and 4")
+	}
+	startDate := time.Date(yearInt, time.Month(3*quarterInt-2), 1, 0, 0, 0, 0, time.UTC)
+	return Period{
+		Start:       startDate,
+		End:         startDate.AddDate(0, 3, -1),
+		Granularity: Quarterly,
+		Label:       fmt.Sprintf("%d-Q%d", yearInt, quarterInt),
+	}, nil
+}
+
+func Year(year string) (Period, error) {
+	yearInt, err := parseYear(year)
+	if err != nil {
+		return Period{}, err
+	}
+	startDate := time.Date(yearInt, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return Period{
+		Start:       startDate,
+		End:         startDate.AddDate(1, 0, -1),
+		Granularity: Yearly,
+		Label:       fmt.Sprint(yearInt),
+	}, nil
+}
+
+// Resolves a relative expression to a period that ends on the last day with complete data at the time now:
+//   - yesterday: the last complete day
+//   - last-N-days: the N days up to the last complete day, e.g. last-7-days or last-30-days
+//   - month-to-date: from the first day of the month of the last complete day
+//   - year-to-date: from the first day of the year of the last complete day
+func FromExpression(expression string, now time.Time) (Period, error) {
+	end := LastCompleteDay(now)
+	p := Period{End: end, Granularity: Relative, Label: expression}
+	switch expression {
+	case "yesterday":
+		p.Start = end
+	case "month-to-date":
+		p.Start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "year-to-date":
+		p.Start = time.Date(end.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		match := lastNDays.FindStringSubmatch(expression)
+		if match == nil {
+			return Period{}, fmt.Errorf(status400+": %q must be one of yesterday, last-N-days, month-to-date, year-to-date", expression)
+		}
+		days, err := strconv.Atoi(match[1])
+		if err != nil || days < 1 || days > MaxRelativeDays {
+			return Period{}, fmt.Errorf(status400+": last-N-days must be between 1 and %d days", MaxRelativeDays)
+		}
+		p.Start = end.AddDate(0, 0, 1-days)
+	}
+	return p, nil
+}
+
+// Returns the last day the wikipedia API has complete data for at the time now
+func LastCompleteDay(now time.Time) time.Time {
+	lagged := now.UTC().Add(-DataLag)
+	return time.Date(lagged.Year(), lagged.Month(), lagged.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+}
+
+func parseYear(year string) (int, error) {
+	yearInt, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, err
+	}
+	err = utilities.ValidateInputYear(yearInt)
+	if err != nil {
+		return 0, err
+	}
+	return yearInt, nil
+}
+
+// The wikipedia API expects timestamps in the YYYYMMDDHH format
+func Timestamp(date time.Time) string {
+	return date.Format("2006010215")
+}
+
+// Returns the timestamp of the first day of the period
+func (p Period) StartTimestamp() string {
+	return Timestamp(p.Start)
+}
+
+// Returns the timestamp of the last day of the period
+func (p Period) EndTimestamp() string {
+	return Timestamp(p.End)
+}
+
+// Returns true if the period is made of whole months, so monthly data covers it exactly
+func (p Period) MonthAligned() bool {
+	return p.Start.Day() == 1 && p.End.AddDate(0, 0, 1).Day() == 1
+}
+
+// Returns the first day of every month of the period
+func (p Period) Months() []time.Time {
+	var months []time.Time
+	month := time.Date(p.Start.Year(), p.Start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; !month.After(p.End); month = month.AddDate(0, 1, 0) {
+		months = append(months, month)
+	}
+	return months
+}
+
+// Splits the period at the month boundaries, e.g. a week from January 30 to February 5 is split into
+// January 30-31 and February 1-5. Whole months of the period are MonthAligned.
+func (p Period) SplitByMonth() []Period {
+	var parts []Period
+	for _, month := range p.Months() {
+		part := p
+		if month.After(p.Start) {
+			part.Start = month
+		}
+		if monthEnd := month.AddDate(0, 1, -1); monthEnd.Before(p.End) {
+			part.End = monthEnd
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// Returns every day of the period
+func (p Period) Days() []time.Time {
+	var days []time.Time
+	for day := p.Start; !day.After(p.End); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// Returns the period that the compare option (previous or year-ago) compares p with
+func (p Period) Comparison(option string) (Period, error) {
+	switch option {
+	case Previous:
+		return p.Previous(), nil
+	case YearAgo:
+		return p.YearAgo(), nil
+	}
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	return Period{}, fmt.Errorf(status400+": compare must be %s or %s", Previous, YearAgo)
+}
+
+// Returns the period of the same length that ends the day before p starts
+// Periods of whole months move by months, so the previous period of a month is the whole month before it
+func (p Period) Previous() Period {
+	if p.MonthAligned() {
+		months := len(p.Months())
+		return p.shifted(p.Start.AddDate(0, -months, 0), months)
+	}
+	days := len(p.Days())
+	previous := p
+	previous.Start, previous.End = p.Start.AddDate(0, 0, -days), p.Start.AddDate(0, 0, -1)
+	previous.Label = dateRangeLabel(previous)
+	if p.Granularity == Daily {
+		previous.Label = previous.Start.Format("2006-01-02")
+	}
+	return previous
+}
+
+// Returns the same period one year earlier
+// Weeks move by 52 weeks so they start on the same weekday, which is the same week of the year in all but a few
+// years with 53 weeks
+func (p Period) YearAgo() Period {
+	if p.MonthAligned() {
+		return p.shifted(p.Start.AddDate(-1, 0, 0), len(p.Months()))
+	}
+	yearAgo := p
+	if p.Granularity == Weekly {
+		yearAgo.Start, yearAgo.End = p.Start.AddDate(0, 0, -52*7), p.End.AddDate(0, 0, -52*7)
+	} else {
+		yearAgo.Start, yearAgo.End = p.Start.AddDate(-1, 0, 0), p.End.AddDate(-1, 0, 0)
+	}
+	yearAgo.Label = dateRangeLabel(yearAgo)
+	if p.Granularity == Daily {
+		yearAgo.Label = yearAgo.Start.Format("2006-01-02")
+	}
+	return yearAgo
+}
+
+// Returns the period of the months that start on the input date, labeled like the periods of p
+func (p Period) shifted(start time.Time, months int) Period {
+	shifted := p
+	shifted.Start, shifted.End = start, start.AddDate(0, months, -1)
+	switch p.Granularity {
+	case Monthly:
+		shifted.Label = start.Format("2006-01")
+	case Quarterly:
+		shifted.Label = fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())+2)/3)
+	case Yearly:
+		shifted.Label = fmt.Sprint(start.Year())
+	default:
+		shifted.Label = dateRangeLabel(shifted)
+	}
+	return shifted
+}
+
+func dateRangeLabel(p Period) string {
+	return p.Start.Format("2006-01-02") + "/" + p.End.Format("2006-01-02")
+}
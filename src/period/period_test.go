@@ -0,0 +1,426 @@
+package period
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/utilities"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeek(t *testing.T) {
+	testCases := []struct {
+		name          string
+		year          string
+		week          string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedError bool
+	}{
+		{
+			name:          "3rd week of 2023",
+			year:          "2023",
+			week:          "03",
+			expectedStart: time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 1, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "last week of 2020 (which ends in 2021)",
+			year:          "2020",
+			week:          "53",
+			expectedStart: time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "error case: week out of bounds",
+			year:          "2020",
+			week:          "54",
+			expectedError: true,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := Week(tc.year, tc.week, utilities.ISOWeek)
+		if tc.expectedError {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assertExpectedOutput(t, tcNum, got.Start, tc.expectedStart)
+		assertExpectedOutput(t, tcNum, got.End, tc.expectedEnd)
+	}
+}
+
+func TestMonth(t *testing.T) {
+	testCases := []struct {
+		name          string
+		year          string
+		month         string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedError string
+	}{
+		{
+			name:          "February 2020",
+			year:          "2020",
+			month:         "2",
+			expectedStart: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "error case: month > 12",
+			year:          "2023",
+			month:         "14",
+			expectedError: "400 Bad Request: input month must be between 1 and 12",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := Month(tc.year, tc.month)
+		if tc.expectedError != "" {
+			require.Error(t, err)
+			assertExpectedOutput(t, tcNum, err.Error(), tc.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		assertExpectedOutput(t, tcNum, got.Start, tc.expectedStart)
+		assertExpectedOutput(t, tcNum, got.End, tc.expectedEnd)
+	}
+}
+
+func TestQuarter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		year          string
+		quarter       string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedLabel string
+		expectedError string
+	}{
+		{
+			name:          "Q1 of a leap year",
+			year:          "2020",
+			quarter:       "1",
+			expectedStart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2020-Q1",
+		},
+		{
+			name:          "Q4",
+			year:          "2023",
+			quarter:       "4",
+			expectedStart: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2023-Q4",
+		},
+		{
+			name:          "error case: quarter > 4",
+			year:          "2023",
+			quarter:       "5",
+			expectedError: "400 Bad Request: input quarter must be between 1 and 4",
+		},
+		{
+			name:          "error case: year in the future",
+			year:          "3000",
+			quarter:       "1",
+			expectedError: "400 Bad Request: input year cannot be greater than current year",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := Quarter(tc.year, tc.quarter)
+		if tc.expectedError != "" {
+			require.Error(t, err)
+			assertExpectedOutput(t, tcNum, err.Error(), tc.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		assertExpectedOutput(t, tcNum, got.Start, tc.expectedStart)
+		assertExpectedOutput(t, tcNum, got.End, tc.expectedEnd)
+		assertExpectedOutput(t, tcNum, got.Label, tc.expectedLabel)
+		assertExpectedOutput(t, tcNum, got.Granularity, Quarterly)
+	}
+}
+
+func TestYear(t *testing.T) {
+	got, err := Year("2023")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, got.Start, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 0, got.End, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 0, got.Label, "2023")
+	assertExpectedOutput(t, 0, len(got.Months()), 12)
+	assertExpectedOutput(t, 0, len(got.Days()), 365)
+}
+
+func TestMonthAligned(t *testing.T) {
+	quarter, err := Quarter("2023", "2")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, quarter.MonthAligned(), true)
+
+	month, err := Month("2023", "2")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 1, month.MonthAligned(), true)
+	assertExpectedOutput(t, 1, month.Label, "2023-02")
+
+	// ISO week 5 of 2023 runs from January 30 to February 5
+	week, err := Week("2023", "5", utilities.ISOWeek)
+	require.NoError(t, err)
+	assertExpectedOutput(t, 2, week.MonthAligned(), false)
+	assertExpectedOutput(t, 2, week.Label, "2023-W05")
+	assertExpectedOutput(t, 2, len(week.Months()), 2)
+}
+
+func TestFromVars(t *testing.T) {
+	testCases := []struct {
+		name                string
+		vars                map[string]string
+		expectedGranularity string
+		expectedLabel       string
+	}{
+		{
+			name:                "day",
+			vars:                map[string]string{"date": "20230402"},
+			expectedGranularity: Daily,
+			expectedLabel:       "2023-04-02",
+		},
+		{
+			name:                "week",
+			vars:                map[string]string{"year": "2023", "week": "3"},
+			expectedGranularity: Weekly,
+			expectedLabel:       "2023-W03",
+		},
+		{
+			name:                "month",
+			vars:                map[string]string{"year": "2023", "month": "04"},
+			expectedGranularity: Monthly,
+			expectedLabel:       "2023-04",
+		},
+		{
+			name:                "quarter",
+			vars:                map[string]string{"year": "2023", "quarter": "2"},
+			expectedGranularity: Quarterly,
+			expectedLabel:       "2023-Q2",
+		},
+		{
+			name:                "year",
+			vars:                map[string]string{"year": "2023"},
+			expectedGranularity: Yearly,
+			expectedLabel:       "2023",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := FromVars(tc.vars, utilities.ISOWeek)
+		require.NoError(t, err)
+		assertExpectedOutput(t, tcNum, got.Granularity, tc.expectedGranularity)
+		assertExpectedOutput(t, tcNum, got.Label, tc.expectedLabel)
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	got, err := Month("2023", "2")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, got.StartTimestamp(), "2023020100")
+	assertExpectedOutput(t, 0, got.EndTimestamp(), "2023022800")
+}
+
+func TestSplitByMonth(t *testing.T) {
+	p, err := FromExpression("last-30-days", time.Date(2023, 5, 10, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	parts := p.SplitByMonth()
+	assertExpectedOutput(t, 0, len(parts), 2)
+	assertExpectedOutput(t, 0, parts[0].Start, time.Date(2023, 4, 9, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 0, parts[0].End, time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 0, parts[0].MonthAligned(), false)
+	assertExpectedOutput(t, 1, parts[1].Start, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 1, parts[1].End, time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC))
+
+	quarter, err := Quarter("2023", "1")
+	require.NoError(t, err)
+	for i, part := range quarter.SplitByMonth() {
+		assertExpectedOutput(t, 2+i, part.MonthAligned(), true)
+	}
+}
+
+func TestComparison(t *testing.T) {
+	week, err := Week("2023", "03", utilities.ISOWeek)
+	require.NoError(t, err)
+	march, err := Month("2023", "03")
+	require.NoError(t, err)
+	firstQuarter, err := Quarter("2023", "1")
+	require.NoError(t, err)
+	lastWeek, err := FromExpression("last-7-days", time.Date(2023, 5, 10, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	testCases := []struct {
+		name          string
+		period        Period
+		option        string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedLabel string
+	}{
+		{
+			name:          "previous week",
+			period:        week,
+			option:        Previous,
+			expectedStart: time.Date(2023, 1, 9, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2023-01-09/2023-01-15",
+		},
+		{
+			name:          "the same week a year ago starts on the same weekday",
+			period:        week,
+			option:        YearAgo,
+			expectedStart: time.Date(2022, 1, 17, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2022, 1, 23, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2022-01-17/2022-01-23",
+		},
+		{
+			name:          "the month before March is the whole of February",
+			period:        march,
+			option:        Previous,
+			expectedStart: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2023-02",
+		},
+		{
+			name:          "previous quarter",
+			period:        firstQuarter,
+			option:        Previous,
+			expectedStart: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2022-Q4",
+		},
+		{
+			name:          "quarter a year ago",
+			period:        firstQuarter,
+			option:        YearAgo,
+			expectedStart: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2022-Q1",
+		},
+		{
+			name:          "the day before",
+			period:        Period{Start: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), Granularity: Daily},
+			option:        Previous,
+			expectedStart: time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2023-02-28",
+		},
+		{
+			name:          "the 7 days before the last 7 days",
+			period:        lastWeek,
+			option:        Previous,
+			expectedStart: time.Date(2023, 4, 25, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedLabel: "2023-04-25/2023-05-01",
+		},
+	}
+	for i, tc := range testCases {
+		got, err := tc.period.Comparison(tc.option)
+		require.NoError(t, err)
+		assertExpectedOutput(t, i, got.Start, tc.expectedStart)
+		assertExpectedOutput(t, i, got.End, tc.expectedEnd)
+		assertExpectedOutput(t, i, got.Label, tc.expectedLabel)
+		assertExpectedOutput(t, i, got.Granularity, tc.period.Granularity)
+	}
+
+	_, err = march.Comparison("last-year")
+	require.Error(t, err)
+	assertExpectedOutput(t, len(testCases), err.Error(), "400 Bad Request: compare must be previous or year-ago")
+}
+
+func TestFromExpression(t *testing.T) {
+	// Before noon the data of May 9 is not complete yet, so the last complete day on May 10 is May 8
+	now := time.Date(2023, 5, 10, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		expression    string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedError string
+	}{
+		{
+			name:          "yesterday",
+			expression:    "yesterday",
+			expectedStart: time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "last 7 days",
+			expression:    "last-7-days",
+			expectedStart: time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "last 30 days crosses the month",
+			expression:    "last-30-days",
+			expectedStart: time.Date(2023, 4, 9, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "month to date",
+			expression:    "month-to-date",
+			expectedStart: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "year to date",
+			expression:    "year-to-date",
+			expectedStart: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "error case: zero days",
+			expression:    "last-0-days",
+			expectedError: "400 Bad Request: last-N-days must be between 1 and 366 days",
+		},
+		{
+			name:          "error case: unknown expression",
+			expression:    "last-week",
+			expectedError: `400 Bad Request: "last-week" must be one of yesterday, last-N-days, month-to-date, year-to-date`,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := FromExpression(tc.expression, now)
+		if tc.expectedError != "" {
+			require.Error(t, err)
+			assertExpectedOutput(t, tcNum, err.Error(), tc.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		assertExpectedOutput(t, tcNum, got.Start, tc.expectedStart)
+		assertExpectedOutput(t, tcNum, got.End, tc.expectedEnd)
+		assertExpectedOutput(t, tcNum, got.Label, tc.expression)
+	}
+}
+
+func TestLastCompleteDay(t *testing.T) {
+	// The data of May 9 is complete from noon (UTC) on May 10, whatever the time zone of now
+	berlin := time.FixedZone("CEST", 2*60*60)
+	assertExpectedOutput(t, 0, LastCompleteDay(time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)), time.Date(2023, 5, 9, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 1, LastCompleteDay(time.Date(2023, 5, 10, 11, 59, 0, 0, time.UTC)), time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 2, LastCompleteDay(time.Date(2023, 5, 10, 13, 0, 0, 0, berlin)), time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC))
+}
+
+func TestLoadLocation(t *testing.T) {
+	location, err := LoadLocation("")
+	require.NoError(t, err)
+	require.Nil(t, location)
+
+	location, err = LoadLocation("UTC")
+	require.NoError(t, err)
+	require.Nil(t, location)
+
+	location, err = LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, location.String(), "America/Los_Angeles")
+
+	_, err = LoadLocation("Pacific/Nowhere")
+	require.EqualError(t, err, `400 Bad Request: unknown time zone "Pacific/Nowhere"`)
+}
+
+func assertExpectedOutput(t testing.TB, testNum int, got, want interface{}) {
+	t.Helper()
+	if got != want {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package ranking
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// How tied entries are ranked
+type Method string
+
+const (
+	// Every entry gets its own rank, tied entries are ranked by title: 1, 2, 3, 4
+	Ordinal Method = "ordinal"
+	// Tied entries share a rank and the ranks after them skip the shared places: 1, 2, 2, 4
+	Competition Method = "competition"
+	// Tied entries share a rank and the ranks after them follow on: 1, 2, 2, 3
+	Dense Method = "dense"
+)
+
+type Entry struct {
+	Title string
+	Views int
+	Rank  int
+}
+
+// A List is sorted by views (most viewed first), and by title for the same views
+type List []Entry
+
+// Returns the method of the ties query parameter, ordinal if the input is empty
+func ParseMethod(input string) (Method, error) {
+	method := Method(input)
+	switch method {
+	case "":
+		return Ordinal, nil
+	case Ordinal, Competition, Dense:
+		return method, nil
+	}
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	return "", fmt.Errorf(status400+": ties must be %s, %s or %s", Ordinal, Competition, Dense)
+}
+
+// Ranks the titles by their views
+// The order does not depend on the order of the map, so the same views always give the same list
+func Rank(views map[string]int, method Method) List {
+	titles, values, ranks := rankMap(views, method)
+	list := make(List, len(titles))
+	for i := range list {
+		list[i] = Entry{Title: titles[i], Views: values[i], Rank: ranks[i]}
+	}
+	return list
+}
+
+// A title ranked by a score that is not a number of views, e.g. a growth ratio
+type ScoredEntry struct {
+	Title string
+	Score float64
+	Rank  int
+}
+
+// Ranks the titles by their scores (highest first) the same way Rank ranks views, titles with the same score are
+// listed by title
+func RankScores(scores map[string]float64, method Method) []ScoredEntry {
+	titles, values, ranks := rankMap(scores, method)
+	list := make([]ScoredEntry, len(titles))
+	for i := range list {
+		list[i] = ScoredEntry{Title: titles[i], Score: values[i], Rank: ranks[i]}
+	}
+	return list
+}
+
+// Returns the rank of every entry of titles and values, in the order of the entries
+// Entries are ranked like the titles of Rank, but every entry is ranked on its own, so the same title can be
+// listed twice
+func Ranks[V int | float64](titles []string, values []V, method Method) []int {
+	order := sortedOrder(titles, values)
+	ranks := make([]int, len(order))
+	for i, rank := range ranksOf(order, values, method) {
+		ranks[order[i]] = rank
+	}
+	return ranks
+}
+
+// Returns the titles and values of the map sorted from the highest value, with their ranks
+func rankMap[V int | float64](input map[string]V, method Method) ([]string, []V, []int) {
+	titles := make([]string, 0, len(input))
+	values := make([]V, 0, len(input))
+	for title, value := range input {
+		titles = append(titles, title)
+		values = append(values, value)
+	}
+	order := sortedOrder(titles, values)
+	sortedTitles := make([]string, len(order))
+	sortedValues := make([]V, len(order))
+	for i, index := range order {
+		sortedTitles[i], sortedValues[i] = titles[index], values[index]
+	}
+	return sortedTitles, sortedValues, ranksOf(order, values, method)
+}
+
+// Returns the indexes of the entries from the highest to the lowest value, by title for the same value and in the
+// order of the input for the same title
+func sortedOrder[V int | float64](titles []string, values []V) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if values[i] != values[j] {
+			return values[i] > values[j]
+		}
+		return titles[i] < titles[j]
+	})
+	return order
+}
+
+// Returns the ranks of the entries in the sorted order
+func ranksOf[V int | float64](order []int, values []V, method Method) []int {
+	ranks := make([]int, len(order))
+	for i := range ranks {
+		switch {
+		case i == 0:
+			ranks[i] = 1
+		case method == Ordinal || values[order[i]] != values[order[i-1]]:
+			if method == Dense {
+				ranks[i] = ranks[i-1] + 1
+			} else {
+				ranks[i] = i + 1
+			}
+		default:
+			ranks[i] = ranks[i-1]
+		}
+	}
+	return ranks
+}
+
+// Returns the first n entries of the list, or the whole list if it is shorter
+func (l List) Top(n int) List {
+	if len(l) > n {
+		return l[:n]
+	}
+	return l
+}
+
+// Returns the titles of the list in order
+func (l List) Titles() []string {
+	titles := make([]string, len(l))
+	for i, entry := range l {
+		titles[i] = entry.Title
+	}
+	return titles
+}
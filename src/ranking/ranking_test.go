@@ -0,0 +1,108 @@
+package ranking
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	views := map[string]int{
+		"Google_Bard":     500,
+		"ChatGPT":         900,
+		"Albert_Einstein": 500,
+		"YouTube":         300,
+	}
+	testCases := []struct {
+		name          string
+		method        Method
+		expectedRanks []int
+	}{
+		{
+			name:          "ordinal ranks break ties by title",
+			method:        Ordinal,
+			expectedRanks: []int{1, 2, 3, 4},
+		},
+		{
+			name:          "competition ranks skip the shared places",
+			method:        Competition,
+			expectedRanks: []int{1, 2, 2, 4},
+		},
+		{
+			name:          "dense ranks do not skip places",
+			method:        Dense,
+			expectedRanks: []int{1, 2, 2, 3},
+		},
+	}
+	expectedTitles := []string{"ChatGPT", "Albert_Einstein", "Google_Bard", "YouTube"}
+	for tcNum, tc := range testCases {
+		got := Rank(views, tc.method)
+		if !reflect.DeepEqual(got.Titles(), expectedTitles) {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got.Titles(), expectedTitles)
+		}
+		ranks := make([]int, len(got))
+		for i, entry := range got {
+			ranks[i] = entry.Rank
+		}
+		if !reflect.DeepEqual(ranks, tc.expectedRanks) {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, ranks, tc.expectedRanks)
+		}
+	}
+}
+
+func TestRankScores(t *testing.T) {
+	scores := map[string]float64{"Riser": 29.7, "Newcomer": 14.9, "Steady": 1, "Main_Page": 1}
+	got := RankScores(scores, Competition)
+	expected := []ScoredEntry{
+		{Title: "Riser", Score: 29.7, Rank: 1},
+		{Title: "Newcomer", Score: 14.9, Rank: 2},
+		{Title: "Main_Page", Score: 1, Rank: 3},
+		{Title: "Steady", Score: 1, Rank: 3},
+	}
+	require.Equal(t, expected, got)
+}
+
+func TestRanks(t *testing.T) {
+	// the same title can be listed twice, e.g. an article compared with itself
+	titles := []string{"YouTube", "ChatGPT", "Google_Bard", "ChatGPT"}
+	views := []int{300, 900, 500, 500}
+	require.Equal(t, []int{4, 1, 3, 2}, Ranks(titles, views, Ordinal))
+	require.Equal(t, []int{4, 1, 2, 2}, Ranks(titles, views, Competition))
+	require.Equal(t, []int{3, 1, 2, 2}, Ranks(titles, views, Dense))
+	require.Equal(t, []int{2, 1}, Ranks([]string{"Steady", "Riser"}, []float64{1, 29.7}, Ordinal))
+}
+
+func TestRankIsStable(t *testing.T) {
+	views := map[string]int{}
+	for _, title := range []string{"E", "D", "C", "B", "A", "F", "G", "H"} {
+		views[title] = 10
+	}
+	first := Rank(views, Ordinal)
+	// maps are iterated in a random order, the ranking must not depend on it
+	for i := 0; i < 20; i++ {
+		require.Equal(t, first, Rank(views, Ordinal))
+	}
+	require.Equal(t, []string{"A", "B", "C"}, first.Top(3).Titles())
+}
+
+func TestTop(t *testing.T) {
+	list := Rank(map[string]int{"A": 3, "B": 2}, Ordinal)
+	require.Len(t, list.Top(1), 1)
+	require.Len(t, list.Top(10), 2)
+	require.Equal(t, []string{}, List{}.Titles())
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod("")
+	require.NoError(t, err)
+	require.Equal(t, Ordinal, method)
+
+	method, err = ParseMethod("dense")
+	require.NoError(t, err)
+	require.Equal(t, Dense, method)
+
+	_, err = ParseMethod("average")
+	require.Error(t, err)
+	require.Equal(t, "400 Bad Request: ties must be ordinal, competition or dense", err.Error())
+}
@@ -0,0 +1,123 @@
+package redirects
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/titles"
+)
+
+// Move this to the config file
+const apiURL = "https://en.wikipedia.org/w/api.php"
+
+// APISource looks up the redirects with the MediaWiki API of the wiki
+type APISource struct {
+	URL string
+	// Project of the wiki, titles are normalized the way it stores them
+	Project string
+}
+
+func NewAPISource() *APISource {
+	return &APISource{URL: apiURL, Project: filter.DefaultProject}
+}
+
+type queryResponse struct {
+	Query struct {
+		Redirects []struct {
+			From string
+			To   string
+		}
+		Pages []struct {
+			Title     string
+			Missing   bool
+			Redirects []struct {
+				Title string
+			}
+		}
+	}
+	Continue map[string]string
+}
+
+func (s *APISource) Resolve(title string) (string, error) {
+	params := url.Values{
+		"action":        {"query"},
+		"titles":        {title},
+		"redirects":     {"1"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+	}
+	response, err := s.query(params)
+	if err != nil {
+		return "", err
+	}
+
+	// The API follows the redirect and returns the page it points to
+	if len(response.Query.Pages) == 0 || response.Query.Pages[0].Missing {
+		return titles.Normalize(s.Project, title)
+	}
+	return titles.Normalize(s.Project, response.Query.Pages[0].Title)
+}
+
+func (s *APISource) Redirects(article string) ([]string, error) {
+	params := url.Values{
+		"action":        {"query"},
+		"titles":        {article},
+		"prop":          {"redirects"},
+		"rdlimit":       {"max"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+	}
+
+	var redirects []string
+	for {
+		response, err := s.query(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range response.Query.Pages {
+			for _, redirect := range page.Redirects {
+				title, err := titles.Normalize(s.Project, redirect.Title)
+				if err != nil {
+					return nil, err
+				}
+				redirects = append(redirects, title)
+			}
+		}
+
+		// Articles with many redirects are returned in several pages
+		if response.Continue == nil {
+			return redirects, nil
+		}
+		for key, value := range response.Continue {
+			params.Set(key, value)
+		}
+	}
+}
+
+func (s *APISource) query(params url.Values) (queryResponse, error) {
+	response, err := http.Get(s.URL + "?" + params.Encode())
+	if err != nil {
+		return queryResponse{}, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		return queryResponse{}, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return queryResponse{}, fmt.Errorf(response.Status + ": failed to look up redirects: " + strings.TrimSpace(string(responseData)))
+	}
+
+	var result queryResponse
+	err = json.Unmarshal(responseData, &result)
+	if err != nil {
+		return queryResponse{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package redirects
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/titles"
+)
+
+// How the redirects of an article are taken into account
+type Mode string
+
+const (
+	// The article is used as requested
+	None Mode = ""
+	// Redirects are replaced by the article they redirect to
+	Resolve Mode = "resolve"
+	// The views of all the redirects of the article are added to the views of the article
+	Merge Mode = "merge"
+)
+
+// A Source knows which titles redirect to which articles
+type Source interface {
+	// Returns the title of the article the input redirects to, or the input itself if it is not a redirect
+	Resolve(title string) (string, error)
+	// Returns the titles that redirect to the article
+	Redirects(article string) ([]string, error)
+}
+
+var (
+	sourceMu sync.RWMutex
+	source   Source
+)
+
+// Sets the source used by Titles, a nil source disables redirect resolution
+func SetSource(s Source) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	source = s
+}
+
+func ParseMode(input string) (Mode, error) {
+	mode := Mode(input)
+	if mode != None && mode != Resolve && mode != Merge {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return None, fmt.Errorf(status400+": redirects must be %s or %s", Resolve, Merge)
+	}
+	return mode, nil
+}
+
+// Returns the titles whose views make up the views of the article for the mode
+// The first title is always the article itself, or the article it redirects to
+func Titles(article string, mode Mode) ([]string, error) {
+	sourceMu.RLock()
+	s := source
+	sourceMu.RUnlock()
+	if mode == None || s == nil {
+		return []string{article}, nil
+	}
+
+	canonical, err := s.Resolve(article)
+	if err != nil {
+		return nil, err
+	}
+	if mode == Resolve {
+		return []string{canonical}, nil
+	}
+
+	redirects, err := s.Redirects(canonical)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{canonical}, redirects...), nil
+}
+
+// FileSource reads the redirects from a JSON file that maps every redirect to the article it redirects to,
+// so redirects can be resolved offline:
+//
+//	{"Einstein": "Albert_Einstein", "A._Einstein": "Albert_Einstein"}
+type FileSource struct {
+	project   string
+	targets   map[string]string
+	redirects map[string][]string
+}
+
+func NewFileSource(path string) (*FileSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mapping map[string]string
+	err = json.Unmarshal(data, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redirects file %s: %w", path, err)
+	}
+	return NewMapSource(filter.DefaultProject, mapping)
+}
+
+// Builds a source from a map of redirects to the articles they redirect to on the project
+// Titles are normalized the way the project stores them, see titles.Normalize
+func NewMapSource(project string, mapping map[string]string) (*FileSource, error) {
+	s := &FileSource{
+		project:   project,
+		targets:   map[string]string{},
+		redirects: map[string][]string{},
+	}
+	for redirect, target := range mapping {
+		redirect, err := titles.Normalize(project, redirect)
+		if err != nil {
+			return nil, err
+		}
+		target, err := titles.Normalize(project, target)
+		if err != nil {
+			return nil, err
+		}
+		s.targets[redirect] = target
+		s.redirects[target] = append(s.redirects[target], redirect)
+	}
+	for _, redirects := range s.redirects {
+		sort.Strings(redirects)
+	}
+	return s, nil
+}
+
+func (s *FileSource) Resolve(title string) (string, error) {
+	title, err := titles.Normalize(s.project, title)
+	if err != nil {
+		return "", err
+	}
+	if target, ok := s.targets[title]; ok {
+		return target, nil
+	}
+	return title, nil
+}
+
+func (s *FileSource) Redirects(article string) ([]string, error) {
+	article, err := titles.Normalize(s.project, article)
+	if err != nil {
+		return nil, err
+	}
+	return s.redirects[article], nil
+}
@@ -0,0 +1,129 @@
+package redirects
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTitles(t *testing.T) {
+	source, err := NewMapSource("en.wikipedia", map[string]string{
+		"Einstein":       "Albert_Einstein",
+		"A. Einstein":    "Albert_Einstein",
+		"albert_einstin": "Albert_Einstein",
+	})
+	require.NoError(t, err)
+	SetSource(source)
+	defer SetSource(nil)
+
+	testCases := []struct {
+		name           string
+		article        string
+		mode           Mode
+		expectedOutput []string
+	}{
+		{
+			name:           "article is used as requested without a mode",
+			article:        "Einstein",
+			mode:           None,
+			expectedOutput: []string{"Einstein"},
+		},
+		{
+			name:           "redirect is resolved to the article",
+			article:        "Einstein",
+			mode:           Resolve,
+			expectedOutput: []string{"Albert_Einstein"},
+		},
+		{
+			name:           "title that is not a redirect is resolved to itself",
+			article:        "ChatGPT",
+			mode:           Resolve,
+			expectedOutput: []string{"ChatGPT"},
+		},
+		{
+			name:           "redirect is merged with the article and all its redirects",
+			article:        "einstein",
+			mode:           Merge,
+			expectedOutput: []string{"Albert_Einstein", "A._Einstein", "Albert_einstin", "Einstein"},
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := Titles(tc.article, tc.mode)
+		require.NoError(t, err)
+		if !reflect.DeepEqual(got, tc.expectedOutput) {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got, tc.expectedOutput)
+		}
+	}
+}
+
+func TestMapSourceOfCaseSensitiveProject(t *testing.T) {
+	// Wiktionary keeps the case of the first letter, so "iphone" is a different page than "IPhone"
+	source, err := NewMapSource("en.wiktionary", map[string]string{"iphone": "iPhone"})
+	require.NoError(t, err)
+
+	got, err := source.Resolve("iphone")
+	require.NoError(t, err)
+	require.Equal(t, "iPhone", got)
+	gotRedirects, err := source.Redirects("iPhone")
+	require.NoError(t, err)
+	require.Equal(t, []string{"iphone"}, gotRedirects)
+
+	_, err = NewMapSource("en.wikipedia", map[string]string{"Einstein": "Albert[Einstein]"})
+	require.EqualError(t, err, `400 Bad Request: title contains the invalid character '['`)
+}
+
+func TestNewFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redirects.json")
+	err := os.WriteFile(path, []byte(`{"Einstein": "Albert_Einstein"}`), 0o600)
+	require.NoError(t, err)
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+	got, err := source.Resolve("Einstein")
+	require.NoError(t, err)
+	require.Equal(t, "Albert_Einstein", got)
+
+	err = os.WriteFile(path, []byte(`["Einstein"]`), 0o600)
+	require.NoError(t, err)
+	_, err = NewFileSource(path)
+	require.Error(t, err)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("merge")
+	require.NoError(t, err)
+	require.Equal(t, Merge, mode)
+
+	_, err = ParseMode("follow")
+	require.EqualError(t, err, "400 Bad Request: redirects must be resolve or merge")
+}
+
+func TestAPISource(t *testing.T) {
+	// Responses of the MediaWiki API, the redirects are returned in two pages
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("redirects") == "1":
+			w.Write([]byte(`{"query":{"redirects":[{"from":"Einstein","to":"Albert Einstein"}],"pages":[{"pageid":736,"title":"Albert Einstein"}]}}`))
+		case query.Get("rdcontinue") == "":
+			w.Write([]byte(`{"continue":{"rdcontinue":"736|2","continue":"||"},"query":{"pages":[{"title":"Albert Einstein","redirects":[{"title":"Einstein"}]}]}}`))
+		default:
+			w.Write([]byte(`{"query":{"pages":[{"title":"Albert Einstein","redirects":[{"title":"A. Einstein"}]}]}}`))
+		}
+	}))
+	defer server.Close()
+	source := &APISource{URL: server.URL}
+
+	got, err := source.Resolve("Einstein")
+	require.NoError(t, err)
+	require.Equal(t, "Albert_Einstein", got)
+
+	gotRedirects, err := source.Redirects("Albert_Einstein")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Einstein", "A._Einstein"}, gotRedirects)
+}
@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Returns the arithmetic mean of the values, or 0 if there are no values
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// Returns the population standard deviation of the values, or 0 if there are no values
+func StdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := Mean(values)
+	sum := 0.0
+	for _, value := range values {
+		sum += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// Returns the median of the values, or 0 if there are no values
+func Median(values []float64) float64 {
+	return Percentile(values, 50)
+}
+
+// Returns the p-th percentile (0 to 100) of the values, or 0 if there are no values
+// Percentiles between two values are interpolated linearly, the same way spreadsheets compute them
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	position := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// Returns the smallest of the values, or 0 if there are no values
+func Min(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	min := values[0]
+	for _, value := range values[1:] {
+		min = math.Min(min, value)
+	}
+	return min
+}
+
+// Returns the largest of the values, or 0 if there are no values
+func Max(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	max := values[0]
+	for _, value := range values[1:] {
+		max = math.Max(max, value)
+	}
+	return max
+}
+
+// Returns the median absolute deviation of the values from their median, or 0 if there are no values
+// Unlike the standard deviation it is not inflated by a few extreme values
+func MedianAbsoluteDeviation(values []float64) float64 {
+	median := Median(values)
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - median)
+	}
+	return Median(deviations)
+}
+
+// Returns the Gini coefficient of the values: 0 when all the values are equal, close to 1 when a single value
+// makes up almost the whole sum. It is 0 if there are no values or they add up to 0.
+func Gini(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum, weightedSum := 0.0, 0.0
+	for i, value := range sorted {
+		sum += value
+		weightedSum += float64(i+1) * value
+	}
+	if sum == 0 {
+		return 0
+	}
+	n := float64(len(sorted))
+	return 2*weightedSum/(n*sum) - (n+1)/n
+}
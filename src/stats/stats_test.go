@@ -0,0 +1,137 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMean(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []float64
+		expectedOutput float64
+	}{
+		{
+			name:           "mean of 4 values",
+			input:          []float64{2, 4, 4, 6},
+			expectedOutput: 4,
+		},
+		{
+			name:           "mean of no values",
+			input:          []float64{},
+			expectedOutput: 0,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := Mean(tc.input)
+		assertFloat(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []float64
+		expectedOutput float64
+	}{
+		{
+			name:           "standard deviation of 8 values",
+			input:          []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			expectedOutput: 2,
+		},
+		{
+			name:           "standard deviation of constant values",
+			input:          []float64{3, 3, 3},
+			expectedOutput: 0,
+		},
+		{
+			name:           "standard deviation of no values",
+			input:          nil,
+			expectedOutput: 0,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := StdDev(tc.input)
+		assertFloat(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []float64
+		percentile     float64
+		expectedOutput float64
+	}{
+		{
+			name:           "median of an odd number of values",
+			input:          []float64{9, 1, 5},
+			percentile:     50,
+			expectedOutput: 5,
+		},
+		{
+			name:           "median of an even number of values is interpolated",
+			input:          []float64{4, 1, 3, 2},
+			percentile:     50,
+			expectedOutput: 2.5,
+		},
+		{
+			name:           "90th percentile",
+			input:          []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			percentile:     90,
+			expectedOutput: 10,
+		},
+		{
+			name:           "percentiles outside 0 to 100 are the min and max",
+			input:          []float64{1, 2, 3},
+			percentile:     120,
+			expectedOutput: 3,
+		},
+		{
+			name:           "percentile of no values",
+			input:          nil,
+			percentile:     50,
+			expectedOutput: 0,
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := Percentile(tc.input, tc.percentile)
+		assertFloat(t, tcNum, got, tc.expectedOutput)
+	}
+
+	// the input is not sorted in place
+	input := []float64{3, 1, 2}
+	assertFloat(t, len(testCases), Median(input), 2)
+	assertFloat(t, len(testCases)+1, input[0], 3)
+}
+
+func TestMinMax(t *testing.T) {
+	input := []float64{3, -1, 7, 2}
+	assertFloat(t, 0, Min(input), -1)
+	assertFloat(t, 1, Max(input), 7)
+	assertFloat(t, 2, Min(nil), 0)
+	assertFloat(t, 3, Max(nil), 0)
+}
+
+func TestMedianAbsoluteDeviation(t *testing.T) {
+	// median 2, deviations 1, 1, 0, 0, 2, 4, 7 with median 1
+	assertFloat(t, 0, MedianAbsoluteDeviation([]float64{1, 1, 2, 2, 4, 6, 9}), 1)
+	assertFloat(t, 1, MedianAbsoluteDeviation(nil), 0)
+}
+
+func TestGini(t *testing.T) {
+	assertFloat(t, 0, Gini([]float64{5, 5, 5, 5}), 0)
+	// one of four values holds the whole sum: 2*4*1/(4*1) - 5/4
+	assertFloat(t, 1, Gini([]float64{0, 0, 1, 0}), 0.75)
+	// sorted 1, 2, 3: 2*(1+4+9)/(3*6) - 4/3
+	assertFloat(t, 2, Gini([]float64{3, 1, 2}), 2.0/9)
+	assertFloat(t, 3, Gini(nil), 0)
+	assertFloat(t, 4, Gini([]float64{0, 0}), 0)
+}
+
+func assertFloat(t testing.TB, testNum int, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
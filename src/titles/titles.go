@@ -0,0 +1,79 @@
+package titles
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MediaWiki limits titles to 255 bytes
+const maxLength = 255
+
+// Projects that keep the case of the first letter of titles, e.g. "iPhone" and "IPhone" are different pages
+var caseSensitiveProjects = map[string]bool{
+	"en.wiktionary": true,
+	"de.wiktionary": true,
+	"fr.wiktionary": true,
+}
+
+// Characters that cannot be part of a title
+const illegalCharacters = "#<>[]|{}"
+
+// Returns the title the way MediaWiki stores it, so it can be used with the wikipedia API:
+//   - percent-encoded titles are decoded, including titles that were encoded twice
+//   - the title is normalized to Unicode NFC, so composed and decomposed characters are the same title
+//   - spaces become underscores and repeated or surrounding underscores are removed
+//   - the first letter (of the title and after a namespace) is upper case, unless the project is case-sensitive
+func Normalize(project, title string) (string, error) {
+	title = decode(title)
+	title = norm.NFC.String(title)
+	title = strings.Join(strings.FieldsFunc(title, func(r rune) bool { return r == ' ' || r == '_' }), "_")
+
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if title == "" {
+		return "", fmt.Errorf(status400 + ": title cannot be empty")
+	}
+	if len(title) > maxLength {
+		return "", fmt.Errorf(status400+": title cannot be longer than %d bytes", maxLength)
+	}
+	for _, r := range title {
+		if strings.ContainsRune(illegalCharacters, r) || unicode.IsControl(r) || r == utf8.RuneError {
+			return "", fmt.Errorf(status400+": title contains the invalid character %q", r)
+		}
+	}
+
+	if caseSensitiveProjects[project] {
+		return title, nil
+	}
+	if prefix, rest, found := strings.Cut(title, ":"); found && filter.Namespace(project, title) != filter.Main {
+		return upperFirst(prefix) + ":" + upperFirst(rest), nil
+	}
+	return upperFirst(title), nil
+}
+
+// Titles in URLs can be encoded more than once (e.g. %25C3%2586 for Æ), so decode until nothing changes
+// MediaWiki does not allow percent-encoded sequences in titles, so this cannot change a valid title
+func decode(title string) string {
+	for strings.Contains(title, "%") {
+		decoded, err := url.PathUnescape(title)
+		if err != nil || decoded == title {
+			break
+		}
+		title = decoded
+	}
+	return title
+}
+
+func upperFirst(input string) string {
+	first, size := utf8.DecodeRuneInString(input)
+	if size == 0 {
+		return input
+	}
+	return string(unicode.ToUpper(first)) + input[size:]
+}
@@ -0,0 +1,118 @@
+package titles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name           string
+		project        string
+		title          string
+		expectedOutput string
+	}{
+		{
+			name:           "title is used as is",
+			project:        "en.wikipedia",
+			title:          "Albert_Einstein",
+			expectedOutput: "Albert_Einstein",
+		},
+		{
+			name:           "spaces become underscores",
+			project:        "en.wikipedia",
+			title:          "  Albert   Einstein ",
+			expectedOutput: "Albert_Einstein",
+		},
+		{
+			name:           "first letter is capitalized",
+			project:        "en.wikipedia",
+			title:          "æthelred_the_Unready",
+			expectedOutput: "Æthelred_the_Unready",
+		},
+		{
+			name:           "first letter after a namespace is capitalized",
+			project:        "en.wikipedia",
+			title:          "special:search",
+			expectedOutput: "Special:Search",
+		},
+		{
+			name:           "colon that does not follow a namespace is part of the title",
+			project:        "en.wikipedia",
+			title:          "XXX:_return_of_Xander_Cage",
+			expectedOutput: "XXX:_return_of_Xander_Cage",
+		},
+		{
+			name:           "case-sensitive project keeps the first letter",
+			project:        "en.wiktionary",
+			title:          "iPhone",
+			expectedOutput: "iPhone",
+		},
+		{
+			name:           "percent-encoded title is decoded",
+			project:        "en.wikipedia",
+			title:          "%C3%86thelred_the_Unready",
+			expectedOutput: "Æthelred_the_Unready",
+		},
+		{
+			name:           "title encoded twice is decoded",
+			project:        "en.wikipedia",
+			title:          "%25C3%2586thelred_the_Unready",
+			expectedOutput: "Æthelred_the_Unready",
+		},
+		{
+			name:           "decomposed characters are composed (NFC)",
+			project:        "en.wikipedia",
+			title:          "Beyonce\u0301",
+			expectedOutput: "Beyonc\u00e9",
+		},
+		{
+			name:           "encoded slash and question mark are part of the title",
+			project:        "en.wikipedia",
+			title:          "AC%2FDC",
+			expectedOutput: "AC/DC",
+		},
+		{
+			name:           "encoded question mark is part of the title",
+			project:        "en.wikipedia",
+			title:          "Who_Framed_Roger_Rabbit%3F",
+			expectedOutput: "Who_Framed_Roger_Rabbit?",
+		},
+	}
+	for tcNum, tc := range testCases {
+		got, err := Normalize(tc.project, tc.title)
+		require.NoError(t, err)
+		if got != tc.expectedOutput {
+			t.Errorf("test %d failed: got %v want %v", tcNum+1, got, tc.expectedOutput)
+		}
+	}
+}
+
+func TestNormalizeInvalidTitle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		title         string
+		expectedError string
+	}{
+		{
+			name:          "empty title",
+			title:         " _ ",
+			expectedError: "400 Bad Request: title cannot be empty",
+		},
+		{
+			name:          "title with a fragment",
+			title:         "Albert_Einstein%23Early_life",
+			expectedError: `400 Bad Request: title contains the invalid character '#'`,
+		},
+		{
+			name:          "title with brackets",
+			title:         "[[Albert_Einstein]]",
+			expectedError: `400 Bad Request: title contains the invalid character '['`,
+		},
+	}
+	for _, tc := range testCases {
+		_, err := Normalize("en.wikipedia", tc.title)
+		require.EqualError(t, err, tc.expectedError)
+	}
+}
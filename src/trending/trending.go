@@ -0,0 +1,156 @@
+package trending
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/articles"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/ranking"
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/stats"
+)
+
+const (
+	MaxWindow   = 7
+	MaxBaseline = 28
+	limit       = 10
+)
+
+type Riser struct {
+	Article         string
+	Views           int
+	BaselineAverage float64
+	GrowthRatio     float64
+	ZScore          float64
+	Rank            int
+}
+
+// curl "http://localhost:8080/articles/trending/20230301?window=1&baseline=7"
+// Compares the daily average views of every article in the window that ends on the input date against the
+// days of the trailing baseline right before it, and returns the fastest risers
+// The views come from the daily top lists. An article that is not listed on a baseline day is assumed to have the
+// views of the last article of that day's list, the most it can have had, so articles that just entered the lists
+// do not get a huge growth from an empty baseline. An article that is not listed on a window day has 0 views.
+// Articles that do not pass the filter are dropped before ranking
+func GetTrendingArticles(date time.Time, window, baseline int, articleFilter filter.Filter) ([]Riser, error) {
+	if window < 1 || window > MaxWindow || baseline < 1 || baseline > MaxBaseline {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return nil, fmt.Errorf(status400+": window must be between 1 and %d days and baseline between 1 and %d days", MaxWindow, MaxBaseline)
+	}
+
+	// Days are ordered from the oldest baseline day to the last day of the window
+	days := baseline + window
+	firstDay := date.AddDate(0, 0, 1-days)
+	dailyViews, listMinimums, err := getDailyViews(firstDay, days, articleFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	risers := rankRisers(dailyViews, listMinimums, baseline)
+	if len(risers) > limit {
+		risers = risers[:limit]
+	}
+	return risers, nil
+}
+
+// Fetches the top lists of the days concurrently and returns the views of every article on each day, and the views
+// of the last article of each list (before filtering), or 0 for an empty list
+func getDailyViews(firstDay time.Time, days int, articleFilter filter.Filter) ([]map[string]int, []int, error) {
+	dailyViews := make([]map[string]int, days)
+	listMinimums := make([]int, days)
+	errs := make([]error, days)
+	var wg sync.WaitGroup
+	for i := 0; i < days; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			topArticles, err := articles.GetTopArticlesByDay(firstDay.AddDate(0, 0, i))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			dailyViews[i] = map[string]int{}
+			for j, article := range topArticles {
+				if j == 0 || article.Views < listMinimums[i] {
+					listMinimums[i] = article.Views
+				}
+				if !articleFilter.Allows(article.Article) {
+					continue
+				}
+				dailyViews[i][article.Article] = article.Views
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return dailyViews, listMinimums, nil
+}
+
+// Scores every article listed during the window against the first baseline days and ranks them by growth
+// Baseline days without the article count the views of the last article of the list of the day
+func rankRisers(dailyViews []map[string]int, listMinimums []int, baseline int) []Riser {
+	window := len(dailyViews) - baseline
+	var risers []Riser
+	for article := range windowArticles(dailyViews[baseline:]) {
+		baselineViews := make([]float64, baseline)
+		for i := 0; i < baseline; i++ {
+			dayViews, listed := dailyViews[i][article]
+			if !listed {
+				dayViews = listMinimums[i]
+			}
+			baselineViews[i] = float64(dayViews)
+		}
+		views := 0
+		for _, day := range dailyViews[baseline:] {
+			views += day[article]
+		}
+		windowAverage := float64(views) / float64(window)
+		baselineAverage := stats.Mean(baselineViews)
+
+		// Add one view to both sides so baselines of days with empty lists get a finite ratio, and use a
+		// deviation of at least one view so flat baselines do not divide by zero
+		stdDev := stats.StdDev(baselineViews)
+		if stdDev < 1 {
+			stdDev = 1
+		}
+		risers = append(risers, Riser{
+			Article:         article,
+			Views:           views,
+			BaselineAverage: baselineAverage,
+			GrowthRatio:     (windowAverage + 1) / (baselineAverage + 1),
+			ZScore:          (windowAverage - baselineAverage) / stdDev,
+		})
+	}
+
+	// Risers with the same growth are ranked by title, the same way as the articles of the top lists
+	growth := map[string]float64{}
+	byArticle := map[string]Riser{}
+	for _, riser := range risers {
+		growth[riser.Article] = riser.GrowthRatio
+		byArticle[riser.Article] = riser
+	}
+	ranked := make([]Riser, 0, len(risers))
+	for _, entry := range ranking.RankScores(growth, ranking.Ordinal) {
+		riser := byArticle[entry.Title]
+		riser.Rank = entry.Rank
+		ranked = append(ranked, riser)
+	}
+	return ranked
+}
+
+func windowArticles(window []map[string]int) map[string]bool {
+	found := map[string]bool{}
+	for _, day := range window {
+		for article := range day {
+			found[article] = true
+		}
+	}
+	return found
+}
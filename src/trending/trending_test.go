@@ -0,0 +1,49 @@
+package trending
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mpaktiti/wikimedia-pageviews-api/src/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankRisers(t *testing.T) {
+	// 3 baseline days followed by a window of 1 day
+	dailyViews := []map[string]int{
+		{"Main_Page": 5000000, "Steady": 1000, "Riser": 100},
+		{"Main_Page": 5100000, "Steady": 1100, "Tail": 100},
+		{"Main_Page": 4900000, "Steady": 900, "Riser": 100},
+		{"Main_Page": 5000000, "Steady": 1000, "Riser": 3000, "Newcomer": 1500},
+	}
+	listMinimums := []int{100, 100, 100, 1000}
+	got := rankRisers(dailyViews, listMinimums, 3)
+
+	require.Len(t, got, 4)
+	assertExpectedOutput(t, 0, got[0].Article, "Riser")
+	// Riser is not listed on the second day and counts the 100 views of the last article of that day
+	assertExpectedOutput(t, 1, got[0].BaselineAverage, 100.0)
+	assertExpectedOutput(t, 2, got[0].Views, 3000)
+	assertExpectedOutput(t, 3, got[0].Rank, 1)
+	// Newcomer is not listed on any baseline day, so its baseline is the views of the last articles of the lists
+	assertExpectedOutput(t, 4, got[1].Article, "Newcomer")
+	assertExpectedOutput(t, 5, got[1].BaselineAverage, 100.0)
+	assertExpectedOutput(t, 6, got[1].GrowthRatio, 1501.0/101)
+	assertExpectedOutput(t, 7, got[2].Article, "Main_Page")
+	assertExpectedOutput(t, 8, got[3].Article, "Steady")
+}
+
+func TestGetTrendingArticlesInvalidInput(t *testing.T) {
+	date := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := GetTrendingArticles(date, 0, 7, filter.Filter{})
+	require.EqualError(t, err, "400 Bad Request: window must be between 1 and 7 days and baseline between 1 and 28 days")
+	_, err = GetTrendingArticles(date, 1, 29, filter.Filter{})
+	require.Error(t, err)
+}
+
+func assertExpectedOutput(t testing.TB, testNum int, got, want interface{}) {
+	t.Helper()
+	if got != want {
+		t.Errorf("test %d failed: got %v want %v", testNum+1, got, want)
+	}
+}
@@ -2,9 +2,11 @@ package utilities
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,7 +28,8 @@ func ParseErrorDetails(response []byte) (string, error) {
 	var errorResponse ErrorResponse
 	err := json.Unmarshal(response, &errorResponse)
 	if err != nil {
-		if err.Error() == "json: cannot unmarshal array into Go struct field ErrorResponse.Detail of type string" {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Value == "array" {
 			// the response contains an array of strings as details, use different object
 			var errorResponse ErrorResponseWithMultipleDetails
 			err := json.Unmarshal(response, &errorResponse)
@@ -63,66 +66,66 @@ func WeekStart(year, week int) time.Time {
 	return t
 }
 
-// Returns the last day of the input month
-func LastDayOfMonth(year, month string) (time.Time, error) {
-	// Convert input year and week to integers
-	yearInt, err := strconv.Atoi(year)
-	if err != nil {
-		return time.Time{}, err
-	}
-	monthInt, err := strconv.Atoi(month)
-	if err != nil {
-		return time.Time{}, err
+// Validate that the input year is not greater than the current year
+func ValidateInputYear(inputYear int) error {
+	currentYear := time.Now().Year()
+	if inputYear > currentYear {
+		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+		return fmt.Errorf(status400 + ": input year cannot be greater than current year")
 	}
-	firstOfMonth := time.Date(yearInt, time.Month(monthInt), 1, 0, 0, 0, 0, time.UTC)
-	lastOfMonth := firstOfMonth.AddDate(0, 1, -1)
+	return nil
+}
 
-	// lastOfMonthAlternative := time.Date(yearInt, time.Month(monthInt+1), 0, 0, 0, 0, 0, time.UTC)
+// Number of calls to the wikipedia API made at the same time by the whole server, whatever the number of requests
+// and of series or lists each of them fetches
+const maxConcurrentCalls = 16
 
-	return lastOfMonth, nil
-}
+var callSlots = make(chan struct{}, maxConcurrentCalls)
 
-// Returns the last week of the input year
-func lastWeekOfYear(year string) (int, error) {
-	yearToInt, err := strconv.Atoi(year)
+// Calls the wikipedia API and returns the response body
+// Anything different than HTTP 200 is returned as an error prefixed with the response status
+// Calls wait for a free slot when maxConcurrentCalls calls are already running
+func CallAPI(url string) ([]byte, error) {
+	callSlots <- struct{}{}
+	defer func() { <-callSlots }()
+	response, err := http.Get(url)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	yearToDate := time.Date(yearToInt, time.January, 1, 0, 0, 0, 0, time.UTC)
-	_, lastWeek := yearToDate.AddDate(1, 0, 0).Add(-time.Nanosecond).ISOWeek()
+	defer response.Body.Close()
 
-	return lastWeek, nil
-}
-
-// Validate that the input week is not greater than the last year of the input year
-func ValidateInputWeek(year string, week int) error {
-	validLastWeek, err := lastWeekOfYear(year)
+	responseData, err := io.ReadAll(response.Body)
 	if err != nil {
-		status500 := fmt.Sprint(http.StatusInternalServerError) + " " + http.StatusText(http.StatusInternalServerError)
-		return fmt.Errorf(status500+": %s", err.Error())
+		return nil, err
 	}
-	if week > validLastWeek {
-		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
-		return fmt.Errorf(status400+": input week cannot be greater than %d", validLastWeek)
+
+	if response.StatusCode != http.StatusOK {
+		errorDetails, err := ParseErrorDetails(responseData)
+		if err != nil {
+			errorDetails = "Failed to process error details"
+		}
+		return nil, fmt.Errorf(response.Status + ": " + errorDetails)
 	}
-	return nil
+
+	return responseData, nil
 }
 
-// Validate that the input year is not greater than the current year
-func ValidateInputYear(inputYear int) error {
-	currentYear := time.Now().Year()
-	if inputYear > currentYear {
+// Wikipedia API expects dates in the YYYYMMDD format
+func FormatDate(date time.Time) string {
+	return date.Format("20060102")
+}
+
+// Parses a date in the YYYYMMDD format, the same format the wikipedia API uses
+func ParseDate(input string) (time.Time, error) {
+	date, err := time.Parse("20060102", input)
+	if err != nil {
 		status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
-		return fmt.Errorf(status400 + ": input year cannot be greater than current year")
+		return time.Time{}, fmt.Errorf(status400+": %q is not a valid date in YYYYMMDD format", input)
 	}
-	return nil
+	return date, nil
 }
 
-// Wikipedia API expects months and days as 2 digits
-// This function adds a zero at the beginning if needed
-func PadString(input string) string {
-	if len(input) == 1 {
-		return "0" + input
-	}
-	return input
+// The wikipedia API answers with 404 when there is no data for an article in the requested dates
+func IsNotFound(err error) bool {
+	return strings.HasPrefix(err.Error(), fmt.Sprint(http.StatusNotFound))
 }
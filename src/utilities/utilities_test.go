@@ -2,6 +2,9 @@ package utilities
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,67 +43,6 @@ func TestWeekStart(t *testing.T) {
 	}
 }
 
-func TestLastDayOfMonth(t *testing.T) {
-	testCases := []struct {
-		name           string
-		year           string
-		month          string
-		expectedOutput time.Time
-	}{
-		{
-			name:           "January 2020",
-			year:           "2020",
-			month:          "01",
-			expectedOutput: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
-		},
-		{
-			name:           "February 2020",
-			year:           "2020",
-			month:          "02",
-			expectedOutput: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
-		},
-		{
-			name:           "February 2022",
-			year:           "2022",
-			month:          "02",
-			expectedOutput: time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC),
-		},
-		{
-			name:           "December 2023",
-			year:           "2023",
-			month:          "12",
-			expectedOutput: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
-		},
-	}
-	for tcNum, tc := range testCases {
-		got, _ := LastDayOfMonth(tc.year, tc.month)
-		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
-	}
-}
-
-func TestPadString(t *testing.T) {
-	testCases := []struct {
-		name           string
-		input          string
-		expectedOutput string
-	}{
-		{
-			name:           "string with 1 character",
-			input:          "1",
-			expectedOutput: "01",
-		},
-		{
-			name:           "string with 2 characters",
-			input:          "01",
-			expectedOutput: "01",
-		},
-	}
-	for tcNum, tc := range testCases {
-		got := PadString(tc.input)
-		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
-	}
-}
-
 func TestParseErrorDetails(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -152,34 +94,53 @@ func TestValidateInputYear(t *testing.T) {
 	}
 }
 
-func TestValidateInputWeek(t *testing.T) {
-	testCases := []struct {
-		name           string
-		year           string
-		week           int
-		expectedOutput error
-	}{
-		{
-			name:           "valid week",
-			year:           "2023",
-			week:           3,
-			expectedOutput: nil,
-		},
-		{
-			name:           "invalid week",
-			year:           "2020",
-			week:           54,
-			expectedOutput: fmt.Errorf("400 Bad Request: input week cannot be greater than 53"),
-		},
-	}
-	for _, tc := range testCases {
-		got := ValidateInputWeek(tc.year, tc.week)
-		if tc.expectedOutput != nil {
-			require.Error(t, got)
-		} else {
-			require.NoError(t, got)
+func TestFormatDate(t *testing.T) {
+	got := FormatDate(time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 0, got, "20230402")
+}
+
+func TestParseDate(t *testing.T) {
+	got, err := ParseDate("20230402")
+	require.NoError(t, err)
+	assertExpectedOutput(t, 0, got, time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC))
+
+	_, err = ParseDate("2023-04-02")
+	require.Error(t, err)
+	assertExpectedOutput(t, 1, err.Error(), `400 Bad Request: "2023-04-02" is not a valid date in YYYYMMDD format`)
+}
+
+func TestCallAPIConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
 		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3*maxConcurrentCalls)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = CallAPI(server.URL)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
 	}
+	require.LessOrEqual(t, maxRunning, maxConcurrentCalls)
 }
 
 func assertExpectedOutput(t testing.TB, testNum int, got, want interface{}) {
@@ -0,0 +1,101 @@
+package utilities
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// How weeks are numbered within a year
+type WeekScheme string
+
+const (
+	// ISO 8601: weeks start on Monday and week 1 is the week with the first Thursday of the year
+	ISOWeek WeekScheme = "iso"
+	// Weeks start on Sunday and week 1 is the week with January 1st
+	USWeek WeekScheme = "us"
+	// Weeks start on Saturday and week 1 is the week with January 1st
+	SaturdayWeek WeekScheme = "saturday"
+	// Broadcast calendar: weeks start on Monday, week 1 is the week with January 1st and the year ends on the
+	// last Sunday of December, so every week belongs to exactly one year
+	BroadcastWeek WeekScheme = "broadcast"
+)
+
+// Returns the scheme of the input, ISO weeks are used if the input is empty
+func ParseWeekScheme(input string) (WeekScheme, error) {
+	scheme := WeekScheme(input)
+	switch scheme {
+	case "":
+		return ISOWeek, nil
+	case ISOWeek, USWeek, SaturdayWeek, BroadcastWeek:
+		return scheme, nil
+	}
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	return "", fmt.Errorf(status400+": weekScheme must be one of %s, %s, %s, %s", ISOWeek, USWeek, SaturdayWeek, BroadcastWeek)
+}
+
+// Returns the first day of the week for the scheme
+func WeekStartForScheme(year, week int, scheme WeekScheme) time.Time {
+	return firstWeekStart(year, scheme).AddDate(0, 0, (week-1)*7)
+}
+
+// Returns the number of weeks of the year for the scheme
+// With US and Saturday weeks the last week of a year is also week 1 of the next year, so a year can have 54 weeks
+func WeeksInYear(year int, scheme WeekScheme) int {
+	firstWeek := firstWeekStart(year, scheme)
+	switch scheme {
+	case USWeek, SaturdayWeek:
+		lastOfYear := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+		return daysBetween(firstWeek, lastOfYear)/7 + 1
+	default:
+		return daysBetween(firstWeek, firstWeekStart(year+1, scheme)) / 7
+	}
+}
+
+// Validate that the input week exists in the input year for the scheme
+func ValidateWeekForScheme(year, week int, scheme WeekScheme) error {
+	status400 := fmt.Sprint(http.StatusBadRequest) + " " + http.StatusText(http.StatusBadRequest)
+	if week < 1 {
+		return fmt.Errorf(status400 + ": input week cannot be less than 1")
+	}
+	if lastWeek := WeeksInYear(year, scheme); week > lastWeek {
+		return fmt.Errorf(status400+": input week cannot be greater than %d", lastWeek)
+	}
+	return nil
+}
+
+func firstWeekStart(year int, scheme WeekScheme) time.Time {
+	firstOfYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	switch scheme {
+	case USWeek:
+		return startOfWeek(firstOfYear, time.Sunday)
+	case SaturdayWeek:
+		return startOfWeek(firstOfYear, time.Saturday)
+	case BroadcastWeek:
+		return startOfWeek(firstOfYear, time.Monday)
+	default:
+		return WeekStart(year, 1)
+	}
+}
+
+// Returns the first day of the week of the date for the scheme
+func WeekStartOfDate(date time.Time, scheme WeekScheme) time.Time {
+	switch scheme {
+	case USWeek:
+		return startOfWeek(date, time.Sunday)
+	case SaturdayWeek:
+		return startOfWeek(date, time.Saturday)
+	default:
+		return startOfWeek(date, time.Monday)
+	}
+}
+
+// Returns the day the week of the date starts on
+func startOfWeek(date time.Time, firstDay time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(firstDay) + 7) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
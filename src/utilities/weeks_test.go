@@ -0,0 +1,186 @@
+package utilities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekStartForScheme(t *testing.T) {
+	testCases := []struct {
+		name           string
+		year           int
+		week           int
+		scheme         WeekScheme
+		expectedOutput time.Time
+	}{
+		{
+			name:           "ISO week 1 of 2020 starts in 2019",
+			year:           2020,
+			week:           1,
+			scheme:         ISOWeek,
+			expectedOutput: time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "ISO week 1 of 2021 starts after January 1st",
+			year:           2021,
+			week:           1,
+			scheme:         ISOWeek,
+			expectedOutput: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "US week 1 of 2023 starts on January 1st (Sunday)",
+			year:           2023,
+			week:           1,
+			scheme:         USWeek,
+			expectedOutput: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "US week 1 of 2021 starts in 2020",
+			year:           2021,
+			week:           1,
+			scheme:         USWeek,
+			expectedOutput: time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "Saturday week 1 of 2023 starts in 2022",
+			year:           2023,
+			week:           1,
+			scheme:         SaturdayWeek,
+			expectedOutput: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "Saturday week 3 of 2022 starts on January 15th",
+			year:           2022,
+			week:           3,
+			scheme:         SaturdayWeek,
+			expectedOutput: time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "broadcast week 1 of 2023 starts in 2022",
+			year:           2023,
+			week:           1,
+			scheme:         BroadcastWeek,
+			expectedOutput: time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "broadcast week 1 of 2024 starts on January 1st (Monday)",
+			year:           2024,
+			week:           1,
+			scheme:         BroadcastWeek,
+			expectedOutput: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for tcNum, tc := range testCases {
+		got := WeekStartForScheme(tc.year, tc.week, tc.scheme)
+		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+func TestWeeksInYear(t *testing.T) {
+	testCases := []struct {
+		name           string
+		year           int
+		scheme         WeekScheme
+		expectedOutput int
+	}{
+		{name: "ISO 2020", year: 2020, scheme: ISOWeek, expectedOutput: 53},
+		{name: "ISO 2023", year: 2023, scheme: ISOWeek, expectedOutput: 52},
+		{name: "US 2000 (leap year starting on Saturday)", year: 2000, scheme: USWeek, expectedOutput: 54},
+		{name: "US 2023", year: 2023, scheme: USWeek, expectedOutput: 53},
+		{name: "US 2022", year: 2022, scheme: USWeek, expectedOutput: 53},
+		{name: "Saturday 2022", year: 2022, scheme: SaturdayWeek, expectedOutput: 53},
+		{name: "broadcast 2023", year: 2023, scheme: BroadcastWeek, expectedOutput: 53},
+		{name: "broadcast 2024", year: 2024, scheme: BroadcastWeek, expectedOutput: 52},
+	}
+	for tcNum, tc := range testCases {
+		got := WeeksInYear(tc.year, tc.scheme)
+		assertExpectedOutput(t, tcNum, got, tc.expectedOutput)
+	}
+}
+
+// Checks the boundaries of every week of every year between 1970 and 2100 for every scheme
+func TestWeekSchemeBoundaries(t *testing.T) {
+	firstDays := map[WeekScheme]time.Weekday{
+		ISOWeek:       time.Monday,
+		USWeek:        time.Sunday,
+		SaturdayWeek:  time.Saturday,
+		BroadcastWeek: time.Monday,
+	}
+	for scheme, firstDay := range firstDays {
+		for year := 1970; year <= 2100; year++ {
+			weeks := WeeksInYear(year, scheme)
+			require.True(t, weeks >= 52 && weeks <= 54, "%s %d has %d weeks", scheme, year, weeks)
+
+			firstOfYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			lastOfYear := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+			firstWeek := WeekStartForScheme(year, 1, scheme)
+			lastWeek := WeekStartForScheme(year, weeks, scheme)
+			nextYearFirstWeek := WeekStartForScheme(year+1, 1, scheme)
+
+			// Every week starts on the first day of the scheme
+			for week := 1; week <= weeks; week++ {
+				require.Equal(t, firstDay, WeekStartForScheme(year, week, scheme).Weekday(), "%s %d/%d", scheme, year, week)
+			}
+
+			switch scheme {
+			case ISOWeek:
+				// Week 1 has January 4th and the weeks match the ISO weeks of the standard library
+				require.True(t, !firstWeek.After(firstOfYear.AddDate(0, 0, 3)) && firstWeek.AddDate(0, 0, 6).After(firstOfYear.AddDate(0, 0, 2)), "%s %d", scheme, year)
+				for week := 1; week <= weeks; week++ {
+					for day := 0; day < 7; day++ {
+						isoYear, isoWeek := WeekStartForScheme(year, week, scheme).AddDate(0, 0, day).ISOWeek()
+						require.Equal(t, []int{year, week}, []int{isoYear, isoWeek})
+					}
+				}
+				require.Equal(t, nextYearFirstWeek, lastWeek.AddDate(0, 0, 7), "%s %d", scheme, year)
+			case USWeek, SaturdayWeek:
+				// Week 1 has January 1st, the last week has December 31st and is also week 1 of the next year unless
+				// December 31st is the last day of the week
+				require.True(t, !firstWeek.After(firstOfYear) && firstWeek.AddDate(0, 0, 6).After(firstOfYear.AddDate(0, 0, -1)), "%s %d", scheme, year)
+				require.True(t, !lastWeek.After(lastOfYear) && lastWeek.AddDate(0, 0, 6).After(lastOfYear.AddDate(0, 0, -1)), "%s %d", scheme, year)
+				if lastWeek.AddDate(0, 0, 6).Equal(lastOfYear) {
+					require.Equal(t, nextYearFirstWeek, lastWeek.AddDate(0, 0, 7), "%s %d", scheme, year)
+				} else {
+					require.Equal(t, nextYearFirstWeek, lastWeek, "%s %d", scheme, year)
+				}
+			case BroadcastWeek:
+				// Week 1 has January 1st and the last week ends on the last Sunday of December
+				require.True(t, !firstWeek.After(firstOfYear) && firstWeek.AddDate(0, 0, 6).After(firstOfYear.AddDate(0, 0, -1)), "%s %d", scheme, year)
+				lastDay := lastWeek.AddDate(0, 0, 6)
+				require.Equal(t, time.December, lastDay.Month(), "%s %d", scheme, year)
+				require.True(t, lastDay.AddDate(0, 0, 7).Year() > year, "%s %d", scheme, year)
+				require.Equal(t, nextYearFirstWeek, lastWeek.AddDate(0, 0, 7), "%s %d", scheme, year)
+			}
+		}
+	}
+}
+
+func TestValidateWeekForScheme(t *testing.T) {
+	require.NoError(t, ValidateWeekForScheme(2000, 54, USWeek))
+	require.EqualError(t, ValidateWeekForScheme(2000, 54, ISOWeek), "400 Bad Request: input week cannot be greater than 52")
+	require.EqualError(t, ValidateWeekForScheme(2023, 0, ISOWeek), "400 Bad Request: input week cannot be less than 1")
+}
+
+func TestParseWeekScheme(t *testing.T) {
+	scheme, err := ParseWeekScheme("")
+	require.NoError(t, err)
+	require.Equal(t, ISOWeek, scheme)
+
+	scheme, err = ParseWeekScheme("broadcast")
+	require.NoError(t, err)
+	require.Equal(t, BroadcastWeek, scheme)
+
+	_, err = ParseWeekScheme("friday")
+	require.EqualError(t, err, "400 Bad Request: weekScheme must be one of iso, us, saturday, broadcast")
+}
+
+func TestWeekStartOfDate(t *testing.T) {
+	// March 1, 2023 is a Wednesday
+	date := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	assertExpectedOutput(t, 0, WeekStartOfDate(date, ISOWeek), time.Date(2023, 2, 27, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 1, WeekStartOfDate(date, USWeek), time.Date(2023, 2, 26, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 2, WeekStartOfDate(date, SaturdayWeek), time.Date(2023, 2, 25, 0, 0, 0, 0, time.UTC))
+	assertExpectedOutput(t, 3, WeekStartOfDate(date, BroadcastWeek), time.Date(2023, 2, 27, 0, 0, 0, 0, time.UTC))
+}